@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"sync-ethereum/internal/app/all_in_one"
+	"sync-ethereum/pkg/util"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	_AllInOneCmd = &cobra.Command{
+		Use:           "all-in-one",
+		Short:         "Start scheduler, crawler, database writer and http server in one process",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Run: func(_ *cobra.Command, _ []string) {
+			app, err := all_in_one.Initialize(_CfgFile)
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+			util.Launch(app.Start, app.Stop, time.Duration(_Timeout)*time.Second)
+		},
+	}
+)
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"sync-ethereum/internal/app/backfill"
+	backfillDelivery "sync-ethereum/internal/delivery/backfill"
+	"sync-ethereum/pkg/util"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	_BackfillFrom        int64
+	_BackfillTo          int64
+	_BackfillSegmentSize int64
+	_BackfillWorkers     int
+	_BackfillCmd         = &cobra.Command{
+		Use:           "backfill",
+		Short:         "Publish a historical block range to the crawler, run the same range again to resume",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Run: func(_ *cobra.Command, _ []string) {
+			app, err := backfill.Initialize(_CfgFile)
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+			util.Launch(func() error {
+				return app.Start(backfillDelivery.Option{
+					From:        big.NewInt(_BackfillFrom),
+					To:          big.NewInt(_BackfillTo),
+					SegmentSize: _BackfillSegmentSize,
+					Workers:     _BackfillWorkers,
+				})
+			}, app.Stop, time.Duration(_Timeout)*time.Second)
+		},
+	}
+)
+
+func init() {
+	_BackfillCmd.Flags().Int64Var(&_BackfillFrom, "from", 0, "first block number of the range")
+	_BackfillCmd.Flags().Int64Var(&_BackfillTo, "to", 0, "last block number of the range")
+	_BackfillCmd.Flags().Int64Var(&_BackfillSegmentSize, "segment-size", 10000, "blocks of each segment")
+	_BackfillCmd.Flags().IntVar(&_BackfillWorkers, "workers", 4, "segments published in parallel")
+	_BackfillCmd.MarkFlagRequired("from")
+	_BackfillCmd.MarkFlagRequired("to")
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"sync-ethereum/internal/app/dlq"
+	dlqDelivery "sync-ethereum/internal/delivery/dlq"
+	"sync-ethereum/pkg/util"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	_DLQReplayIdle time.Duration
+
+	_DLQCmd = &cobra.Command{
+		Use:   "dlq",
+		Short: "Dead-letter topic tool",
+	}
+
+	_DLQReplayCmd = &cobra.Command{
+		Use:           "replay",
+		Short:         "Publish the dead letters back to the topics they failed on",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Run: func(_ *cobra.Command, _ []string) {
+			app, err := dlq.Initialize(_CfgFile)
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+			util.Launch(func() error {
+				return app.Start(dlqDelivery.Option{Idle: _DLQReplayIdle})
+			}, app.Stop, time.Duration(_Timeout)*time.Second)
+		},
+	}
+)
+
+func init() {
+	_DLQReplayCmd.Flags().DurationVar(&_DLQReplayIdle, "idle", 30*time.Second, "stop after no dead letter is received for the duration, 0 keeps running")
+	_DLQCmd.AddCommand(_DLQReplayCmd)
+}
@@ -24,7 +24,7 @@ func Execute() {
 }
 
 func init() {
-	_RootCmd.AddCommand(_HttpCmd, _SchedulerCmd, _CrawlerCmd, _WriterCmd, _MigrationCmd)
+	_RootCmd.AddCommand(_AllInOneCmd, _HttpCmd, _SchedulerCmd, _CrawlerCmd, _WriterCmd, _MigrationCmd, _BackfillCmd, _VerifyCmd, _DLQCmd)
 	_RootCmd.PersistentFlags().StringVar(&_CfgFile, "config", "config/default.config.yaml", "config file")
 	_RootCmd.PersistentFlags().UintVar(&_Timeout, "timeout", 300, "graceful shutdown timeout (second)")
 }
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"sync-ethereum/internal/app/verify"
+	verifyDelivery "sync-ethereum/internal/delivery/verify"
+	"sync-ethereum/pkg/util"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	_VerifyFrom      int64
+	_VerifyTo        int64
+	_VerifyBatchSize int64
+	_VerifyDryRun    bool
+	_VerifyCmd       = &cobra.Command{
+		Use:           "verify",
+		Short:         "Detect missing, incomplete and unlinked blocks in the database and push them to the crawler again",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Run: func(cmd *cobra.Command, _ []string) {
+			app, err := verify.Initialize(_CfgFile)
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+			option := verifyDelivery.Option{
+				From:      big.NewInt(_VerifyFrom),
+				BatchSize: _VerifyBatchSize,
+				DryRun:    _VerifyDryRun,
+			}
+			if cmd.Flags().Changed("to") {
+				option.To = big.NewInt(_VerifyTo)
+			}
+			util.Launch(func() error {
+				return app.Start(option)
+			}, app.Stop, time.Duration(_Timeout)*time.Second)
+		},
+	}
+)
+
+func init() {
+	_VerifyCmd.Flags().Int64Var(&_VerifyFrom, "from", 0, "first block number of the range")
+	_VerifyCmd.Flags().Int64Var(&_VerifyTo, "to", 0, "last block number of the range (default the current block number of the scheduler)")
+	_VerifyCmd.Flags().Int64Var(&_VerifyBatchSize, "batch-size", 1000, "blocks loaded from the database at a time")
+	_VerifyCmd.Flags().BoolVar(&_VerifyDryRun, "dry-run", false, "report the damaged blocks without pushing them")
+}
@@ -0,0 +1,118 @@
+package all_in_one
+
+import (
+	"errors"
+	"fmt"
+	netHttp "net/http"
+	"sync"
+	"sync-ethereum/internal/config"
+	"sync-ethereum/internal/delivery/crawler"
+	"sync-ethereum/internal/delivery/database_writer"
+	"sync-ethereum/internal/delivery/http"
+	"sync-ethereum/internal/delivery/scheduler"
+	"sync-ethereum/internal/repository"
+	"sync-ethereum/internal/service"
+	"sync-ethereum/pkg/mq"
+
+	"github.com/rs/zerolog"
+)
+
+// Application runs the scheduler, crawler, database writer and http server in one process,
+// they share the same queue so the memory driver works
+type Application struct {
+	logger          zerolog.Logger
+	config          config.Config
+	repo            repository.StorageRepository
+	mq              mq.MQ
+	storageSvc      service.StorageService
+	crawlerSvc      service.CrawlerService
+	running         *sync.WaitGroup
+	scheduler       *scheduler.Scheduler
+	crawler         *crawler.Crawler
+	database_writer *database_writer.DatabaseWriter
+	httpServer      *http.HttpServer
+}
+
+// Start migrates the database up and returns once any of the services stops with error
+func (application Application) Start() error {
+	if err := application.repo.MigrateUp(); err != nil {
+		return err
+	}
+	application.logger.Info().Msg("migration up complete")
+
+	starts := []func() error{
+		application.database_writer.Start,
+		application.crawler.Start,
+		application.scheduler.Start,
+		func() error {
+			application.logger.Info().Msgf("http server listen :%d", application.config.HTTP.Port)
+			err := application.httpServer.Run(fmt.Sprintf(":%d", application.config.HTTP.Port))
+			if errors.Is(err, netHttp.ErrServerClosed) {
+				return nil
+			}
+			return err
+		},
+	}
+	application.logger.Info().Msg("all-in-one startup")
+	errs := make(chan error, len(starts))
+	application.running.Add(len(starts))
+	for _, start := range starts {
+		go func(start func() error) {
+			defer application.running.Done()
+			errs <- start()
+		}(start)
+	}
+	for range starts {
+		if err := <-errs; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop stops the producers, then closes the queue and waits for the consumers to return,
+// the shared database and node client are closed once nothing uses them
+func (application Application) Stop() error {
+	application.logger.Info().Msg("shutdown all-in-one ...")
+	defer application.logger.Info().Msg("all-in-one is closed")
+	application.scheduler.Stop()
+	application.crawler.Stop()
+	err := application.httpServer.Stop()
+	if closeErr := application.mq.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	application.running.Wait()
+
+	application.crawlerSvc.Close()
+	if closeErr := application.storageSvc.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func newApplication(
+	logger zerolog.Logger,
+	config config.Config,
+	repo repository.StorageRepository,
+	mq mq.MQ,
+	storageSvc service.StorageService,
+	crawlerSvc service.CrawlerService,
+	scheduler *scheduler.Scheduler,
+	crawler *crawler.Crawler,
+	database_writer *database_writer.DatabaseWriter,
+	httpServer *http.HttpServer,
+) Application {
+	return Application{
+		logger:          logger,
+		config:          config,
+		repo:            repo,
+		mq:              mq,
+		storageSvc:      storageSvc,
+		crawlerSvc:      crawlerSvc,
+		running:         &sync.WaitGroup{},
+		scheduler:       scheduler,
+		crawler:         crawler,
+		database_writer: database_writer,
+		httpServer:      httpServer,
+	}
+}
@@ -0,0 +1,38 @@
+//+build wireinject
+
+//The build tag makes sure the stub is not built in the final build.
+
+package all_in_one
+
+import (
+	"sync-ethereum/internal/config"
+	"sync-ethereum/internal/delivery/crawler"
+	"sync-ethereum/internal/delivery/database_writer"
+	"sync-ethereum/internal/delivery/http"
+	"sync-ethereum/internal/delivery/scheduler"
+	"sync-ethereum/internal/repository/gorm"
+	crawlerSvc "sync-ethereum/internal/service/ethclient_crawler"
+	"sync-ethereum/internal/service/storage"
+	"sync-ethereum/internal/wireset"
+
+	"github.com/google/wire"
+)
+
+func Initialize(configPath string) (Application, error) {
+	wire.Build(
+		newApplication,
+		config.NewConfig,
+		wireset.InitLogger,
+		wireset.InitDatabase,
+		wireset.InitMQ,
+		wireset.InitBlobStore,
+		gorm.NewStorageRepository,
+		crawlerSvc.NewEthClientCrawlerService,
+		storage.NewStorageService,
+		scheduler.NewScheduler,
+		crawler.NewCrawler,
+		database_writer.NewDatabaseWriter,
+		http.NewHttpServer,
+	)
+	return Application{}, nil
+}
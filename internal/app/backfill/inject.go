@@ -0,0 +1,33 @@
+package backfill
+
+import (
+	"sync-ethereum/internal/delivery/backfill"
+
+	"github.com/rs/zerolog"
+)
+
+type Application struct {
+	logger   zerolog.Logger
+	backfill *backfill.Backfill
+}
+
+func (application Application) Start(option backfill.Option) error {
+	application.logger.Info().Msg("backfill startup")
+	return application.backfill.Start(option)
+}
+
+func (application Application) Stop() error {
+	application.logger.Info().Msg("shutdown backfill ...")
+	defer application.logger.Info().Msg("backfill is closed")
+	return application.backfill.Shutdown()
+}
+
+func newApplication(
+	logger zerolog.Logger,
+	backfill *backfill.Backfill,
+) Application {
+	return Application{
+		logger:   logger,
+		backfill: backfill,
+	}
+}
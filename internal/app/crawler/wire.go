@@ -22,6 +22,7 @@ func Initialize(configPath string) (Application, error) {
 		wireset.InitLogger,
 		wireset.InitDatabase,
 		wireset.InitMQ,
+		wireset.InitBlobStore,
 		gorm.NewStorageRepository,
 		storage.NewStorageService,
 		crawlerSvc.NewEthClientCrawlerService,
@@ -35,8 +35,15 @@ func Initialize(configPath string) (Application, error) {
 	}
 	storageRepository := gorm.NewStorageRepository(db)
 	storageService := storage.NewStorageService(storageRepository)
-	crawlerService := ethclient_crawler.NewEthClientCrawlerService(configConfig)
-	crawlerCrawler := crawler.NewCrawler(configConfig, logger, mq, storageService, crawlerService)
+	crawlerService, err := ethclient_crawler.NewEthClientCrawlerService(configConfig, logger)
+	if err != nil {
+		return Application{}, err
+	}
+	store, err := wireset.InitBlobStore(configConfig)
+	if err != nil {
+		return Application{}, err
+	}
+	crawlerCrawler := crawler.NewCrawler(configConfig, logger, mq, storageService, crawlerService, store)
 	application := newApplication(logger, crawlerCrawler)
 	return application, nil
 }
@@ -21,6 +21,7 @@ func Initialize(configPath string) (Application, error) {
 		wireset.InitLogger,
 		wireset.InitDatabase,
 		wireset.InitMQ,
+		wireset.InitBlobStore,
 		gorm.NewStorageRepository,
 		storage.NewStorageService,
 		database_writer.NewDatabaseWriter,
@@ -34,7 +34,11 @@ func Initialize(configPath string) (Application, error) {
 	}
 	storageRepository := gorm.NewStorageRepository(db)
 	storageService := storage.NewStorageService(storageRepository)
-	databaseWriter := database_writer.NewDatabaseWriter(configConfig, logger, mq, storageService)
+	store, err := wireset.InitBlobStore(configConfig)
+	if err != nil {
+		return Application{}, err
+	}
+	databaseWriter := database_writer.NewDatabaseWriter(configConfig, logger, mq, storageService, store)
 	application := newApplication(logger, databaseWriter)
 	return application, nil
 }
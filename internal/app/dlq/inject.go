@@ -0,0 +1,33 @@
+package dlq
+
+import (
+	"sync-ethereum/internal/delivery/dlq"
+
+	"github.com/rs/zerolog"
+)
+
+type Application struct {
+	logger zerolog.Logger
+	replay *dlq.Replay
+}
+
+func (application Application) Start(option dlq.Option) error {
+	application.logger.Info().Msg("dlq replay startup")
+	return application.replay.Start(option)
+}
+
+func (application Application) Stop() error {
+	application.logger.Info().Msg("shutdown dlq replay ...")
+	defer application.logger.Info().Msg("dlq replay is closed")
+	return application.replay.Shutdown()
+}
+
+func newApplication(
+	logger zerolog.Logger,
+	replay *dlq.Replay,
+) Application {
+	return Application{
+		logger: logger,
+		replay: replay,
+	}
+}
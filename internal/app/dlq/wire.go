@@ -0,0 +1,24 @@
+//+build wireinject
+
+//The build tag makes sure the stub is not built in the final build.
+
+package dlq
+
+import (
+	"sync-ethereum/internal/config"
+	"sync-ethereum/internal/delivery/dlq"
+	"sync-ethereum/internal/wireset"
+
+	"github.com/google/wire"
+)
+
+func Initialize(configPath string) (Application, error) {
+	wire.Build(
+		newApplication,
+		config.NewConfig,
+		wireset.InitLogger,
+		wireset.InitMQ,
+		dlq.NewReplay,
+	)
+	return Application{}, nil
+}
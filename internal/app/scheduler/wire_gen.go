@@ -29,7 +29,10 @@ func Initialize(configPath string) (Application, error) {
 	if err != nil {
 		return Application{}, err
 	}
-	crawlerService := ethclient_crawler.NewEthClientCrawlerService(configConfig)
+	crawlerService, err := ethclient_crawler.NewEthClientCrawlerService(configConfig, logger)
+	if err != nil {
+		return Application{}, err
+	}
 	db, err := wireset.InitDatabase(configConfig, logger)
 	if err != nil {
 		return Application{}, err
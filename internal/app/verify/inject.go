@@ -0,0 +1,33 @@
+package verify
+
+import (
+	"sync-ethereum/internal/delivery/verify"
+
+	"github.com/rs/zerolog"
+)
+
+type Application struct {
+	logger zerolog.Logger
+	verify *verify.Verify
+}
+
+func (application Application) Start(option verify.Option) error {
+	application.logger.Info().Msg("verify startup")
+	return application.verify.Start(option)
+}
+
+func (application Application) Stop() error {
+	application.logger.Info().Msg("shutdown verify ...")
+	defer application.logger.Info().Msg("verify is closed")
+	return application.verify.Shutdown()
+}
+
+func newApplication(
+	logger zerolog.Logger,
+	verify *verify.Verify,
+) Application {
+	return Application{
+		logger: logger,
+		verify: verify,
+	}
+}
@@ -1,11 +1,15 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"reflect"
+	"strconv"
 	"strings"
 	"sync-ethereum/pkg/logger"
 	"time"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 )
 
@@ -20,6 +24,7 @@ type Config struct {
 	Scheduler      SchedulerConfig      `mapstructure:"scheduler"`
 	Crawler        CrawlerConfig        `mapstructure:"crawler"`
 	DatabaseWriter DatabaseWriterConfig `mapstructure:"database_writer"`
+	Blob           BlobConfig           `mapstructure:"blob"`
 }
 
 type LoggerConfig struct {
@@ -52,16 +57,65 @@ type DataBaseConfig struct {
 
 type MQConfig struct {
 	Driver               string                     `mapstructure:"driver"`
+	Retry                MQRetryConfig              `mapstructure:"retry"`
+	DeadLetterTopic      string                     `mapstructure:"dead_letter_topic"`
+	MessageEncoding      string                     `mapstructure:"message_encoding"`
+	MemoryOption         MemoryOptionConfig         `mapstructure:"memory_option"`
+	RedisOption          RedisOptionConfig          `mapstructure:"redis_option"`
+	NatsOption           NatsOptionConfig           `mapstructure:"nats_option"`
 	KafkaOption          KafkaOptionConfig          `mapstructure:"kafka_option"`
 	ConfluentKafkaOption ConfluentKafkaOptionConfig `mapstructure:"confluentkafka_option"`
 }
 
+type MQRetryConfig struct {
+	MaxAttempts int           `mapstructure:"max_attempts"`
+	Backoff     time.Duration `mapstructure:"backoff"`
+	MaxBackoff  time.Duration `mapstructure:"max_backoff"`
+}
+
+type MemoryOptionConfig struct {
+	RedeliveryDelay time.Duration `mapstructure:"redelivery_delay"`
+}
+
+type RedisOptionConfig struct {
+	Addr            string        `mapstructure:"addr"`
+	Username        string        `mapstructure:"username"`
+	Password        string        `mapstructure:"password"`
+	DB              int           `mapstructure:"db"`
+	ConsumerGroup   string        `mapstructure:"consumer_group"`
+	Consumer        string        `mapstructure:"consumer"`
+	MaxLen          int64         `mapstructure:"max_len"`
+	BatchSize       int64         `mapstructure:"batch_size"`
+	BlockTimeout    time.Duration `mapstructure:"block_timeout"`
+	ClaimMinIdle    time.Duration `mapstructure:"claim_min_idle"`
+	ClaimInterval   time.Duration `mapstructure:"claim_interval"`
+	RedeliveryDelay time.Duration `mapstructure:"redelivery_delay"`
+}
+
+type NatsOptionConfig struct {
+	URL           string        `mapstructure:"url"`
+	Username      string        `mapstructure:"username"`
+	Password      string        `mapstructure:"password"`
+	Token         string        `mapstructure:"token"`
+	StreamPrefix  string        `mapstructure:"stream_prefix"`
+	Storage       string        `mapstructure:"storage"`
+	Replicas      int           `mapstructure:"replicas"`
+	MaxAge        time.Duration `mapstructure:"max_age"`
+	Durable       string        `mapstructure:"durable"`
+	MaxDeliver    int           `mapstructure:"max_deliver"`
+	AckWait       time.Duration `mapstructure:"ack_wait"`
+	MaxAckPending int           `mapstructure:"max_ack_pending"`
+	FetchBatch    int           `mapstructure:"fetch_batch"`
+	FetchTimeout  time.Duration `mapstructure:"fetch_timeout"`
+}
+
 type KafkaOptionConfig struct {
 	Brokers        []string `mapstructure:"brokers"`
 	ConsumerGroup  string   `mapstructure:"consumer_group"`
 	OffsetsInitial int64    `mapstructure:"offsets_initial"`
 	FetchDefault   int32    `mapstructure:"fetch_default"`
 	RequiredAcks   int16    `mapstructure:"required_acks"`
+	PartitionByKey bool     `mapstructure:"partition_by_key"`
 }
 
 type ConfluentKafkaOptionConfig struct {
@@ -95,11 +149,15 @@ type ConfluentKafkaOptionConfig struct {
 	Retries         int    `mapstructure:"retries"`
 	BatchSize       int    `mapstructure:"batch_size"`
 	FlushWaitMs     int    `mapstructure:"flush_wait_ms"`
+	RawFormat       bool   `mapstructure:"raw_format"`
 
 	// ================ consumer related config ================
 	FetchMaxBytes          int `mapstructure:"fetch_max_bytes"`
 	MaxPartitionFetchBytes int `mapstructure:"max_partition_fetch_bytes"`
 	PollTimeoutMs          int `mapstructure:"poll_timeout_ms"`
+	RedeliveryDelayMs      int `mapstructure:"redelivery_delay_ms"`
+	MaxRedeliveries        int `mapstructure:"max_redeliveries"`
+	ReleaseDelayMs         int `mapstructure:"release_delay_ms"`
 
 	// ================ auth ================
 	SASlUserName    string `mapstructure:"sasl_username"`
@@ -109,24 +167,127 @@ type ConfluentKafkaOptionConfig struct {
 }
 
 type EthClientConfig struct {
-	URL           string        `mapstructure:"url"`
-	DialTimeout   time.Duration `mapstructure:"dial_timeout"`
-	MaxClientConn int           `mapstructure:"max_client_conn"`
+	URL                 string                    `mapstructure:"url"`
+	Endpoints           []EthClientEndpointConfig `mapstructure:"endpoints"`
+	SubscribeURL        string                    `mapstructure:"subscribe_url"`
+	DialTimeout         time.Duration             `mapstructure:"dial_timeout"`
+	MaxClientConn       int                       `mapstructure:"max_client_conn"`
+	ReceiptBatchSize    int                       `mapstructure:"receipt_batch_size"`
+	MaxRetry            int                       `mapstructure:"max_retry"`
+	HealthCheckInterval time.Duration             `mapstructure:"health_check_interval"`
+	MaxBlockLag         uint64                    `mapstructure:"max_block_lag"`
+	MaxErrorRate        float64                   `mapstructure:"max_error_rate"`
+	RateLimit           float64                   `mapstructure:"rate_limit"`
+	Burst               int                       `mapstructure:"burst"`
+	MethodCosts         map[string]int            `mapstructure:"method_costs"`
+	RateLimitBackoff    time.Duration             `mapstructure:"rate_limit_backoff"`
+}
+
+// EthClientEndpointConfig is a node endpoint, it can be written as `url|weight|rate_limit|burst` in environment variable
+type EthClientEndpointConfig struct {
+	URL       string  `mapstructure:"url"`
+	Weight    int     `mapstructure:"weight"`
+	RateLimit float64 `mapstructure:"rate_limit"`
+	Burst     int     `mapstructure:"burst"`
+}
+
+// GetEndpoints returns the configured endpoints, or the single url with weight 1,
+// the endpoint without its own rate limit inherits the client one
+func (c EthClientConfig) GetEndpoints() []EthClientEndpointConfig {
+	endpoints := c.Endpoints
+	if len(endpoints) == 0 && len(c.URL) > 0 {
+		endpoints = []EthClientEndpointConfig{{URL: c.URL, Weight: 1}}
+	}
+	result := make([]EthClientEndpointConfig, len(endpoints))
+	for i, endpoint := range endpoints {
+		if endpoint.RateLimit <= 0 {
+			endpoint.RateLimit = c.RateLimit
+			endpoint.Burst = c.Burst
+		}
+		result[i] = endpoint
+	}
+	return result
+}
+
+// GetMethodCost returns the quota cost of the json-rpc method, default 1
+func (c EthClientConfig) GetMethodCost(method string) int {
+	if cost, ok := c.MethodCosts[method]; ok && cost > 0 {
+		return cost
+	}
+	return 1
+}
+
+func _StringToEthClientEndpointHookFunc() mapstructure.DecodeHookFuncType {
+	return func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if f.Kind() != reflect.String || t != reflect.TypeOf(EthClientEndpointConfig{}) {
+			return data, nil
+		}
+		values := strings.Split(strings.TrimSpace(data.(string)), "|")
+		if len(values) > 4 {
+			return nil, fmt.Errorf("invalid endpoint [%s]", data)
+		}
+		endpoint := EthClientEndpointConfig{URL: values[0], Weight: 1}
+		if len(values) > 1 && len(values[1]) > 0 {
+			weight, err := strconv.Atoi(values[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid weight of endpoint [%s]: %w", data, err)
+			}
+			endpoint.Weight = weight
+		}
+		if len(values) > 2 && len(values[2]) > 0 {
+			rateLimit, err := strconv.ParseFloat(values[2], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rate limit of endpoint [%s]: %w", data, err)
+			}
+			endpoint.RateLimit = rateLimit
+		}
+		if len(values) > 3 && len(values[3]) > 0 {
+			burst, err := strconv.Atoi(values[3])
+			if err != nil {
+				return nil, fmt.Errorf("invalid burst of endpoint [%s]: %w", data, err)
+			}
+			endpoint.Burst = burst
+		}
+		return endpoint, nil
+	}
 }
 
 type SchedulerConfig struct {
+	Stability      Stability  `mapstructure:"stability"`
 	UnstableNumber int        `mapstructure:"unstable_num"`
 	StartAt        int64      `mapstructure:"start_at"`
 	Sync           SyncConfig `mapstructure:"sync"`
 	BatchLimit     int64      `mapstructure:"batch_limit"`
 }
+type Stability string
+
+const (
+	// StabilityDepth marks the blocks unstable_num behind the head as stable
+	StabilityDepth Stability = "depth"
+	// StabilityFinality marks the blocks covered by the finalized tag of the node as stable
+	StabilityFinality Stability = "finality"
+)
+
 type SyncConfig struct {
+	Mode     SyncMode      `mapstructure:"mode"`
 	Interval time.Duration `mapstructure:"interval"`
 }
+
+type SyncMode string
+
+const (
+	// SyncModePoll polls the head on the interval
+	SyncModePoll SyncMode = "poll"
+	// SyncModeSubscribe syncs on every newHeads notification, and keeps polling in case the subscription drops
+	SyncModeSubscribe SyncMode = "subscribe"
+)
+
 type CrawlerConfig struct {
-	Topic    string        `mapstructure:"topic"`
-	PoolSize int           `mapstructure:"pool_size"`
-	Timeout  time.Duration `mapstructure:"timeout"`
+	Topic                string        `mapstructure:"topic"`
+	PoolSize             int           `mapstructure:"pool_size"`
+	Timeout              time.Duration `mapstructure:"timeout"`
+	MaxReorgDepth        int           `mapstructure:"max_reorg_depth"`
+	RateLimitMaxAttempts int           `mapstructure:"rate_limit_max_attempts"`
 }
 
 type DatabaseWriterConfig struct {
@@ -135,6 +296,30 @@ type DatabaseWriterConfig struct {
 	Timeout  time.Duration `mapstructure:"timeout"`
 }
 
+// BlobConfig is the blob store of the payloads too large for the message queue,
+// the crawler publishes a reference instead of a block larger than Threshold bytes
+type BlobConfig struct {
+	Driver           string                     `mapstructure:"driver"`
+	Threshold        int                        `mapstructure:"threshold"`
+	FilesystemOption BlobFilesystemOptionConfig `mapstructure:"filesystem_option"`
+	S3Option         BlobS3OptionConfig         `mapstructure:"s3_option"`
+}
+
+type BlobFilesystemOptionConfig struct {
+	Dir string `mapstructure:"dir"`
+}
+
+type BlobS3OptionConfig struct {
+	Endpoint        string        `mapstructure:"endpoint"`
+	Region          string        `mapstructure:"region"`
+	Bucket          string        `mapstructure:"bucket"`
+	AccessKeyID     string        `mapstructure:"access_key_id"`
+	SecretAccessKey string        `mapstructure:"secret_access_key"`
+	Prefix          string        `mapstructure:"prefix"`
+	PathStyle       bool          `mapstructure:"path_style"`
+	Timeout         time.Duration `mapstructure:"timeout"`
+}
+
 func NewConfig(configPath string) (Config, error) {
 	var file *os.File
 	file, _ = os.Open(configPath)
@@ -170,12 +355,48 @@ func NewConfig(configPath string) (Config, error) {
 
 	/* mq */
 	v.SetDefault("mq.driver", "")
+	v.SetDefault("mq.retry.max_attempts", 5)
+	v.SetDefault("mq.retry.backoff", time.Second)
+	v.SetDefault("mq.retry.max_backoff", 30*time.Second)
+	v.SetDefault("mq.dead_letter_topic", "")    // disabled
+	v.SetDefault("mq.message_encoding", "json") // json, protobuf
+	/* memory option */
+	v.SetDefault("mq.memory_option.redelivery_delay", time.Second)
+	/* redis stream option */
+	v.SetDefault("mq.redis_option.addr", "localhost:6379")
+	v.SetDefault("mq.redis_option.username", "")
+	v.SetDefault("mq.redis_option.password", "")
+	v.SetDefault("mq.redis_option.db", 0)
+	v.SetDefault("mq.redis_option.consumer_group", "")
+	v.SetDefault("mq.redis_option.consumer", "")
+	v.SetDefault("mq.redis_option.max_len", 0) // unlimited
+	v.SetDefault("mq.redis_option.batch_size", 10)
+	v.SetDefault("mq.redis_option.block_timeout", time.Second)
+	v.SetDefault("mq.redis_option.claim_min_idle", 5*time.Minute)
+	v.SetDefault("mq.redis_option.claim_interval", 30*time.Second)
+	v.SetDefault("mq.redis_option.redelivery_delay", time.Second)
+	/* nats jetstream option */
+	v.SetDefault("mq.nats_option.url", "nats://localhost:4222")
+	v.SetDefault("mq.nats_option.username", "")
+	v.SetDefault("mq.nats_option.password", "")
+	v.SetDefault("mq.nats_option.token", "")
+	v.SetDefault("mq.nats_option.stream_prefix", "")
+	v.SetDefault("mq.nats_option.storage", "file") // file, memory
+	v.SetDefault("mq.nats_option.replicas", 1)
+	v.SetDefault("mq.nats_option.max_age", 0) // unlimited
+	v.SetDefault("mq.nats_option.durable", "")
+	v.SetDefault("mq.nats_option.max_deliver", -1) // unlimited
+	v.SetDefault("mq.nats_option.ack_wait", 30*time.Second)
+	v.SetDefault("mq.nats_option.max_ack_pending", 0) // server default
+	v.SetDefault("mq.nats_option.fetch_batch", 10)
+	v.SetDefault("mq.nats_option.fetch_timeout", time.Second)
 	/* watermill kafka option */
 	v.SetDefault("mq.kafka_option.brokers", []string{})
 	v.SetDefault("mq.kafka_option.consumer_group", "")
 	v.SetDefault("mq.kafka_option.offsets_initial", -2) // OffsetNewest = -1, OffsetOldest = -2
 	v.SetDefault("mq.kafka_option.fetch_default", 1024*1024)
 	v.SetDefault("mq.kafka_option.required_acks", 1) // NoResponse = 0, WaitForLocal = 1, WaitForAll = -1
+	v.SetDefault("mq.kafka_option.partition_by_key", false)
 	/* confluent kafka option */
 	v.SetDefault("mq.confluentkafka_option.brokers", []string{})
 	v.SetDefault("mq.confluentkafka_option.client_id", "")
@@ -193,9 +414,13 @@ func NewConfig(configPath string) (Config, error) {
 	v.SetDefault("mq.confluentkafka_option.retries", 5)
 	v.SetDefault("mq.confluentkafka_option.batch_size", 0)
 	v.SetDefault("mq.confluentkafka_option.flush_wait_ms", 0)
+	v.SetDefault("mq.confluentkafka_option.raw_format", false)
 	v.SetDefault("mq.confluentkafka_option.fetch_max_bytes", 0)
 	v.SetDefault("mq.confluentkafka_option.max_partition_fetch_bytes", 0)
 	v.SetDefault("mq.confluentkafka_option.poll_timeout_ms", 100)
+	v.SetDefault("mq.confluentkafka_option.redelivery_delay_ms", 1000)
+	v.SetDefault("mq.confluentkafka_option.max_redeliveries", 5)
+	v.SetDefault("mq.confluentkafka_option.release_delay_ms", 30000)
 	v.SetDefault("mq.confluentkafka_option.sasl_username", "")
 	v.SetDefault("mq.confluentkafka_option.sasl_password", "")
 	v.SetDefault("mq.confluentkafka_option.sasl_mechanisms", "")
@@ -203,12 +428,25 @@ func NewConfig(configPath string) (Config, error) {
 
 	/* eth client */
 	v.SetDefault("eth_client.url", "")
+	v.SetDefault("eth_client.endpoints", []EthClientEndpointConfig{})
+	v.SetDefault("eth_client.subscribe_url", "")
 	v.SetDefault("eth_client.dial_timeout", 10*time.Second)
 	v.SetDefault("eth_client.max_client_conn", 100)
+	v.SetDefault("eth_client.receipt_batch_size", 100)
+	v.SetDefault("eth_client.max_retry", 2)
+	v.SetDefault("eth_client.health_check_interval", 10*time.Second)
+	v.SetDefault("eth_client.max_block_lag", 5)
+	v.SetDefault("eth_client.max_error_rate", 0.5)
+	v.SetDefault("eth_client.rate_limit", 0) // unlimited
+	v.SetDefault("eth_client.burst", 0)
+	v.SetDefault("eth_client.method_costs", map[string]int{})
+	v.SetDefault("eth_client.rate_limit_backoff", time.Second)
 
 	/* scheduler */
+	v.SetDefault("scheduler.stability", StabilityDepth)
 	v.SetDefault("scheduler.unstable_num", 20)
 	v.SetDefault("scheduler.start_at", 0)
+	v.SetDefault("scheduler.sync.mode", SyncModePoll)
 	v.SetDefault("scheduler.sync.interval", 10*time.Second)
 	v.SetDefault("scheduler.batch_limit", 100)
 
@@ -216,17 +454,36 @@ func NewConfig(configPath string) (Config, error) {
 	v.SetDefault("crawler.topic", "")
 	v.SetDefault("crawler.pool_size", 200)
 	v.SetDefault("crawler.timeout", 10*time.Second)
+	v.SetDefault("crawler.max_reorg_depth", 64)
+	v.SetDefault("crawler.rate_limit_max_attempts", 5)
 
 	/* database writer */
 	v.SetDefault("database_writer.topic", "")
 	v.SetDefault("database_writer.pool_size", 200)
 	v.SetDefault("database_writer.timeout", 10*time.Second)
 
+	/* blob */
+	v.SetDefault("blob.driver", "") // disabled
+	v.SetDefault("blob.threshold", 512*1024)
+	v.SetDefault("blob.filesystem_option.dir", "blob")
+	v.SetDefault("blob.s3_option.endpoint", "")
+	v.SetDefault("blob.s3_option.region", "us-east-1")
+	v.SetDefault("blob.s3_option.bucket", "")
+	v.SetDefault("blob.s3_option.access_key_id", "")
+	v.SetDefault("blob.s3_option.secret_access_key", "")
+	v.SetDefault("blob.s3_option.prefix", "")
+	v.SetDefault("blob.s3_option.path_style", false)
+	v.SetDefault("blob.s3_option.timeout", 30*time.Second)
+
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.ReadConfig(file)
 
 	var config Config
-	if err := v.Unmarshal(&config); err != nil {
+	if err := v.Unmarshal(&config, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		_StringToEthClientEndpointHookFunc(),
+	))); err != nil {
 		return config, err
 	}
 
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEthClientEndpoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		yaml    string
+		want    []EthClientEndpointConfig
+		wantErr bool
+	}{
+		{
+			name: "url only",
+			yaml: "eth_client:\n  url: http://a\n  rate_limit: 5\n  burst: 10\n",
+			want: []EthClientEndpointConfig{{URL: "http://a", Weight: 1, RateLimit: 5, Burst: 10}},
+		},
+		{
+			name: "env url",
+			env:  "http://a",
+			want: []EthClientEndpointConfig{{URL: "http://a", Weight: 1}},
+		},
+		{
+			name: "env weights and limits",
+			env:  "http://a|3|10|20, http://b|1",
+			want: []EthClientEndpointConfig{
+				{URL: "http://a", Weight: 3, RateLimit: 10, Burst: 20},
+				{URL: "http://b", Weight: 1},
+			},
+		},
+		{
+			name: "env inherits client rate limit",
+			env:  "http://a||2.5,http://b",
+			yaml: "eth_client:\n  rate_limit: 7\n  burst: 9\n",
+			want: []EthClientEndpointConfig{
+				{URL: "http://a", Weight: 1, RateLimit: 2.5},
+				{URL: "http://b", Weight: 1, RateLimit: 7, Burst: 9},
+			},
+		},
+		{
+			name: "yaml endpoints",
+			yaml: "eth_client:\n  endpoints:\n    - url: http://a\n      weight: 2\n    - http://b|4|1|1\n",
+			want: []EthClientEndpointConfig{
+				{URL: "http://a", Weight: 2},
+				{URL: "http://b", Weight: 4, RateLimit: 1, Burst: 1},
+			},
+		},
+		{
+			name: "no endpoint",
+			yaml: "eth_client:\n  url: \"\"\n",
+			want: []EthClientEndpointConfig{},
+		},
+		{name: "too many fields", env: "http://a|1|1|1|1", wantErr: true},
+		{name: "invalid weight", env: "http://a|x", wantErr: true},
+		{name: "invalid rate limit", env: "http://a|1|x", wantErr: true},
+		{name: "invalid burst", env: "http://a|1|1|x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if len(tt.env) > 0 {
+				t.Setenv("ETH_CLIENT_ENDPOINTS", tt.env)
+			}
+
+			config, err := NewConfig(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NewConfig succeeded with endpoints %+v, want error", config.EthClient.Endpoints)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewConfig: %v", err)
+			}
+			if got := config.EthClient.GetEndpoints(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync-ethereum/internal/config"
+	"sync-ethereum/internal/model"
+	"sync-ethereum/internal/service"
+	"sync-ethereum/pkg/mq"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/errgroup"
+)
+
+// the progress of a segment is saved after every _CheckpointInterval published blocks
+const _CheckpointInterval = 1000
+
+func NewBackfill(config config.Config, logger zerolog.Logger, mq mq.MQ, crawler service.CrawlerService, storageSvc service.StorageService) *Backfill {
+	return &Backfill{
+		config:     config,
+		logger:     logger,
+		mq:         mq,
+		crawler:    crawler,
+		storageSvc: storageSvc,
+	}
+}
+
+type Option struct {
+	From        *big.Int
+	To          *big.Int
+	SegmentSize int64
+	Workers     int
+}
+
+// Backfill publishes a historical block range to the crawler segment by segment,
+// it records the progress in its own table and never touches the current block number of the scheduler
+type Backfill struct {
+	config     config.Config
+	logger     zerolog.Logger
+	mq         mq.MQ
+	crawler    service.CrawlerService
+	storageSvc service.StorageService
+
+	lock   sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (b *Backfill) Start(option Option) error {
+	if option.From.Sign() < 0 || option.From.Cmp(option.To) > 0 {
+		return fmt.Errorf("invalid range from %s to %s", option.From, option.To)
+	}
+	if option.SegmentSize <= 0 {
+		return fmt.Errorf("invalid segment size %d", option.SegmentSize)
+	}
+	if option.Workers <= 0 {
+		option.Workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.lock.Lock()
+	b.cancel = cancel
+	b.done = make(chan struct{})
+	b.lock.Unlock()
+	defer close(b.done)
+	defer cancel()
+
+	segments, err := b.storageSvc.PlanBackfill(ctx, option.From, option.To, option.SegmentSize)
+	if err != nil {
+		return err
+	}
+	stability, err := b._Stability(ctx)
+	if err != nil {
+		return err
+	}
+
+	pending := make(chan *model.BackfillSegment, len(segments))
+	for i := range segments {
+		if segments[i].Status != model.BackfillStatusDone {
+			pending <- &segments[i]
+		}
+	}
+	close(pending)
+	b.logger.Info().Str("from", option.From.String()).Str("to", option.To.String()).Int("segments", len(segments)).Int("pending", len(pending)).Msg("backfill planned")
+
+	errGroup, errCtx := errgroup.WithContext(ctx)
+	for i := 0; i < option.Workers; i++ {
+		errGroup.Go(func() error {
+			for segment := range pending {
+				if errCtx.Err() != nil {
+					return nil
+				}
+				if err := b._PublishSegment(errCtx, segment, stability); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if err := errGroup.Wait(); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		b.logger.Warn().Msg("backfill interrupted, run the same range again to resume")
+		return nil
+	}
+	b.logger.Info().Str("from", option.From.String()).Str("to", option.To.String()).Msg("backfill complete")
+	return nil
+}
+
+// _Stability returns how to mark a block by the head of the node, in the same way as the scheduler
+func (b *Backfill) _Stability(ctx context.Context) (model.Stability, error) {
+	if b.config.Scheduler.Stability == config.StabilityFinality {
+		safe, finalized, err := b.crawler.GetFinality(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return model.FinalityStability(safe, finalized), nil
+	}
+
+	head, err := b.crawler.GetBlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return model.DepthStability(head, b.config.Scheduler.UnstableNumber), nil
+}
+
+func (b *Backfill) _PublishSegment(ctx context.Context, segment *model.BackfillSegment, stability model.Stability) error {
+	segment.Status = model.BackfillStatusRunning
+	if err := b.storageSvc.UpdateBackfillSegment(context.Background(), segment); err != nil {
+		return err
+	}
+
+	to := segment.ToBlockNumber.BigInt()
+	number := segment.NextBlockNumber.BigInt()
+	published := 0
+	for ; number.Cmp(to) <= 0 && ctx.Err() == nil; number = new(big.Int).Add(number, big.NewInt(1)) {
+		isStable, finality := stability(number)
+		messageBytes, err := model.MarshalCrawlerMessage(model.MessageEncoding(b.config.MQ.MessageEncoding), model.CrawlerMessage{
+			IsStable:    isStable,
+			BlockNumber: model.GormBigInt(*number),
+			Finality:    finality,
+		})
+		if err != nil {
+			return err
+		}
+		if err := b.mq.Publish(b.config.Crawler.Topic, model.MessageKey(number), messageBytes); err != nil {
+			return err
+		}
+
+		published++
+		if published%_CheckpointInterval == 0 {
+			if err := b._Checkpoint(segment, new(big.Int).Add(number, big.NewInt(1)), to); err != nil {
+				return err
+			}
+		}
+	}
+	return b._Checkpoint(segment, number, to)
+}
+
+// _Checkpoint saves the next block of the segment, it is saved even if ctx is canceled so the backfill can be resumed
+func (b *Backfill) _Checkpoint(segment *model.BackfillSegment, next, to *big.Int) error {
+	segment.NextBlockNumber = model.GormBigInt(*next)
+	if next.Cmp(to) > 0 {
+		segment.Status = model.BackfillStatusDone
+	}
+	if err := b.storageSvc.UpdateBackfillSegment(context.Background(), segment); err != nil {
+		return err
+	}
+	b.logger.Info().Str("from", segment.FromBlockNumber.BigInt().String()).Str("to", to.String()).Str("next", next.String()).Str("status", string(segment.Status)).Msg("backfill checkpoint")
+	return nil
+}
+
+func (b *Backfill) Shutdown() error {
+	b.lock.Lock()
+	cancel, done := b.cancel, b.done
+	b.lock.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+
+	b.crawler.Close()
+	if err := b.storageSvc.Close(); err != nil {
+		return err
+	}
+	return b.mq.Close()
+}
@@ -3,23 +3,33 @@ package crawler
 import (
 	"context"
 	"encoding/json"
+	"math/big"
+	"sync"
 	"sync-ethereum/internal/config"
+	pkgErrors "sync-ethereum/internal/errors"
 	"sync-ethereum/internal/model"
 	"sync-ethereum/internal/service"
+	"sync-ethereum/pkg/blob"
 	"sync-ethereum/pkg/mq"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
 
-func NewCrawler(config config.Config, logger zerolog.Logger, mq mq.MQ, storageSvc service.StorageService, crawler service.CrawlerService) *Crawler {
+const _MaxRateLimitBackoff = 30 * time.Second
+
+func NewCrawler(config config.Config, logger zerolog.Logger, mq mq.MQ, storageSvc service.StorageService, crawler service.CrawlerService, blobStore blob.Store) *Crawler {
 	return &Crawler{
 		config:     config,
 		logger:     logger,
 		mq:         mq,
 		storageSvc: storageSvc,
 		crawler:    crawler,
+		blobStore:  blobStore,
+		done:       make(chan struct{}),
 	}
 }
 
@@ -29,15 +39,20 @@ type Crawler struct {
 	mq         mq.MQ
 	storageSvc service.StorageService
 	crawler    service.CrawlerService
+	blobStore  blob.Store
+	done       chan struct{}
+	closeOnce  sync.Once
 }
 
 func (c *Crawler) Start() error {
-	err := c.mq.Subscribe(context.Background(), c.config.Crawler.PoolSize, c.config.Crawler.Topic, func(key string, data []byte) (bool, error) {
+	err := c.mq.Subscribe(context.Background(), c.config.Crawler.PoolSize, c.config.Crawler.Topic, c._WithRateLimitBackoff(func(key string, data []byte) (bool, error) {
 		ctx, cancel := context.WithTimeout(context.Background(), c.config.Crawler.Timeout)
 		defer cancel()
-		crawlerMessage := model.CrawlerMessage{}
-		err := json.Unmarshal(data, &crawlerMessage)
+		crawlerMessage, err := model.UnmarshalCrawlerMessage(data)
 		if err != nil {
+			if errors.Is(err, model.ErrUnsupportedEnvelopeVersion) {
+				return false, errors.WithMessage(mq.ErrUnprocessable, err.Error()) // a newer message is left to an upgraded crawler
+			}
 			return true, err
 		}
 
@@ -48,86 +63,336 @@ func (c *Crawler) Start() error {
 			return false, errors.WithMessagef(err, "get block error, block_number: %d", number.Int64())
 		}
 
+		orphaned, err := c._DetectReorg(ctx, block)
+		if err != nil {
+			return false, errors.WithMessagef(err, "detect reorg error, block_number: %d", number.Int64())
+		}
+		if len(orphaned) > 0 {
+			if err := c._Rollback(ctx, block, orphaned, crawlerMessage); err != nil {
+				return false, errors.WithMessagef(err, "rollback orphaned blocks error, block_number: %d", number.Int64())
+			}
+		}
+
 		modelBlock := model.Block{
 			BlockNumber: model.GormBigInt(*block.Number()),
 			BlockHash:   block.Hash().Hex(),
 			BlockTime:   block.Time(),
 			ParentHash:  block.ParentHash().Hex(),
 			IsStable:    crawlerMessage.IsStable,
+			Finality:    crawlerMessage.Finality,
+			Coinbase:    block.Coinbase().Hex(),
+			GasLimit:    block.GasLimit(),
+			GasUsed:     block.GasUsed(),
+			BaseFee:     _GormBigIntPtr(block.BaseFee()), // block before london has no base fee
+			Difficulty:  model.GormBigInt(*block.Difficulty()),
+			Nonce:       block.Nonce(),
+			MixDigest:   block.MixDigest().Hex(),
+			StateRoot:   block.Root().Hex(),
+			TxRoot:      block.TxHash().Hex(),
+			ReceiptRoot: block.ReceiptHash().Hex(),
+			ExtraData:   model.BlockData(block.Extra()),
+			Size:        block.Size(),
+			UncleCount:  len(block.Uncles()),
 			Transaction: make([]*model.Transaction, block.Transactions().Len()),
+			Uncles:      make([]*model.Uncle, len(block.Uncles())),
+			Withdrawals: make([]*model.Withdrawal, len(block.Withdrawals())), // block before shanghai has no withdrawals
 		}
 
-		for idx, tx := range block.Body().Transactions {
-			receipt, err := c.crawler.GetTransactionReceipt(ctx, tx.Hash())
-			if err != nil {
-				return false, errors.WithMessagef(err, "get transaction receipt error, block_number: %d, txHash: %s", number.Int64(), tx.Hash().String())
+		for idx, withdrawal := range block.Withdrawals() {
+			modelBlock.Withdrawals[idx] = &model.Withdrawal{
+				BlockNumber:    modelBlock.BlockNumber,
+				Index:          withdrawal.Index,
+				ValidatorIndex: withdrawal.Validator,
+				Address:        withdrawal.Address.Hex(),
+				Amount:         withdrawal.Amount,
+			}
+		}
+
+		for position, uncle := range block.Uncles() {
+			modelBlock.Uncles[position] = &model.Uncle{
+				BlockNumber: modelBlock.BlockNumber,
+				Position:    position,
+				UncleHash:   uncle.Hash().Hex(),
+				UncleNumber: model.GormBigInt(*uncle.Number),
+				ParentHash:  uncle.ParentHash.Hex(),
+				Coinbase:    uncle.Coinbase.Hex(),
+				Difficulty:  model.GormBigInt(*uncle.Difficulty),
+				GasLimit:    uncle.GasLimit,
+				GasUsed:     uncle.GasUsed,
+				UncleTime:   uncle.Time,
+				ExtraData:   model.BlockData(uncle.Extra),
 			}
+		}
+
+		receipts, err := c.crawler.GetBlockReceipts(ctx, block)
+		if err != nil {
+			return false, errors.WithMessagef(err, "get block receipts error, block_number: %d", number.Int64())
+		}
 
-			from := ""
-			signer := types.NewEIP155Signer(tx.ChainId())
-			sender, err := signer.Sender(tx)
+		for idx, tx := range block.Body().Transactions {
+			receipt := receipts[idx]
+
+			// the latest signer accepts every transaction type, and falls back to homestead for unprotected legacy transaction
+			sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
 			if err != nil {
-				cancel()
-				continue
+				return false, errors.WithMessagef(err, "get transaction sender error, block_number: %d, txHash: %s", number.Int64(), tx.Hash().String())
 			}
-			from = sender.Hex()
+			from := sender.Hex()
 
 			to := ""
 			if tx.To() != nil {
 				to = tx.To().Hex()
 			}
+			contractAddress := ""
+			if receipt.ContractAddress != (common.Address{}) {
+				contractAddress = receipt.ContractAddress.Hex()
+			}
+			effectiveGasPrice := receipt.EffectiveGasPrice
+			if effectiveGasPrice == nil { // node before london does not return effectiveGasPrice
+				effectiveGasPrice = tx.GasPrice()
+			}
+			var gasTipCap, gasFeeCap, blobGasFeeCap *model.GormBigInt
+			if tx.Type() != types.LegacyTxType && tx.Type() != types.AccessListTxType {
+				gasTipCap = _GormBigIntPtr(tx.GasTipCap())
+				gasFeeCap = _GormBigIntPtr(tx.GasFeeCap())
+			}
+			if tx.Type() == types.BlobTxType {
+				blobGasFeeCap = _GormBigIntPtr(tx.BlobGasFeeCap())
+			}
+			accessList, err := _MarshalNotEmpty(tx.AccessList(), len(tx.AccessList()))
+			if err != nil {
+				return true, err // format error, not retry
+			}
+			blobHashes, err := _MarshalNotEmpty(tx.BlobHashes(), len(tx.BlobHashes()))
+			if err != nil {
+				return true, err // format error, not retry
+			}
 			modelTx := &model.Transaction{
-				BlockNumber: model.GormBigInt(*number),
-				TXHash:      tx.Hash().Hex(),
-				From:        from,
-				To:          to,
-				Nonce:       tx.Nonce(),
-				Value:       model.GormBigInt(*tx.Value()),
-				Data:        model.BlockData(tx.Data()),
-				Logs:        make([]*model.TransactionLog, len(receipt.Logs)),
+				BlockNumber:       model.GormBigInt(*number),
+				TXHash:            tx.Hash().Hex(),
+				TxIndex:           uint(idx),
+				From:              from,
+				To:                to,
+				Nonce:             tx.Nonce(),
+				Value:             model.GormBigInt(*tx.Value()),
+				Data:              model.BlockData(tx.Data()),
+				Type:              tx.Type(),
+				Gas:               tx.Gas(),
+				GasPrice:          model.GormBigInt(*tx.GasPrice()),
+				GasTipCap:         gasTipCap,
+				GasFeeCap:         gasFeeCap,
+				AccessList:        accessList,
+				BlobGasFeeCap:     blobGasFeeCap,
+				BlobHashes:        blobHashes,
+				Status:            receipt.Status,
+				GasUsed:           receipt.GasUsed,
+				CumulativeGasUsed: receipt.CumulativeGasUsed,
+				EffectiveGasPrice: model.GormBigInt(*effectiveGasPrice),
+				ContractAddress:   contractAddress,
+				Logs:              make([]*model.TransactionLog, len(receipt.Logs)),
 			}
 
 			for logIdx, log := range receipt.Logs {
 				modelTx.Logs[logIdx] = &model.TransactionLog{
-					TXHash: log.TxHash.Hex(),
-					Index:  uint64(log.Index),
-					Data:   model.BlockData(log.Data),
+					TXHash:      log.TxHash.Hex(),
+					TxIndex:     log.TxIndex,
+					BlockNumber: model.GormBigInt(*new(big.Int).SetUint64(log.BlockNumber)),
+					Address:     log.Address.Hex(),
+					Index:       uint64(log.Index),
+					Data:        model.BlockData(log.Data),
+					Removed:     log.Removed,
 				}
+				modelTx.Logs[logIdx].SetTopics(log.Topics)
 			}
 
 			modelBlock.Transaction[idx] = modelTx
 		}
 
-		b, err := json.Marshal(modelBlock)
+		b, err := model.MarshalBlock(model.MessageEncoding(c.config.MQ.MessageEncoding), modelBlock)
 		if err != nil {
 			return true, err // format error, not retry
 		}
 
 		// pre-written
-		err = c.storageSvc.CreateBlock(ctx, &model.Block{
-			BlockNumber: modelBlock.BlockNumber,
-			BlockHash:   modelBlock.BlockHash,
-			BlockTime:   modelBlock.BlockTime,
-			ParentHash:  modelBlock.ParentHash,
-			IsStable:    false,
-		})
+		header := modelBlock
+		header.IsStable = false
+		if len(header.Finality) > 0 {
+			header.Finality = model.FinalityLatest
+		}
+		header.Transaction = nil
+		header.Uncles = nil
+		header.Withdrawals = nil
+		err = c.storageSvc.CreateBlock(ctx, &header)
 		if err != nil {
 			c.logger.Error().Err(err).Int64("block_number", number.Int64()).Msg("pre-written block error")
 		}
 
-		c.logger.Info().RawJSON("block", b).Int64("block_number", number.Int64()).Msg("push to database writer")
-		err = c.mq.Publish(c.config.DatabaseWriter.Topic, key, b)
+		size := len(b)
+		b, offloaded, err := c._Offload(ctx, number, modelBlock.BlockHash, len(modelBlock.Transaction), b)
 		if err != nil {
+			return false, errors.WithMessagef(err, "offload block error, block_number: %d", number.Int64())
+		}
+
+		c.logger.Info().Int64("block_number", number.Int64()).Int("tx_count", len(modelBlock.Transaction)).Int("message_size", size).Bool("offloaded", offloaded).Msg("push to database writer")
+		err = c.mq.Publish(c.config.DatabaseWriter.Topic, model.MessageKey(number), b)
+		if err != nil {
+			if offloaded {
+				// the retried crawl writes the blob again, a reorg in between would leave it behind
+				key := model.BlobKey(number, modelBlock.BlockHash)
+				if err := c.blobStore.Delete(ctx, key); err != nil {
+					c.logger.Error().Str("blob_key", key).Err(err).Msg("delete blob error")
+				}
+			}
 			return false, err
 		}
 		return true, nil
-	}, func(key string, e error) {
+	}), func(key string, e error) {
 		c.logger.Error().Str("message_key", key).Err(e).Msg("crawler error")
 	})
 	return err
 }
 
+// _WithRateLimitBackoff retries the message after backing off while the node quota is exhausted,
+// the worker is held so the pressure goes to the consumer instead of failing the message,
+// it gives the error back to the mq after the max attempts or on shutdown
+func (c *Crawler) _WithRateLimitBackoff(process func(key string, data []byte) (bool, error)) func(key string, data []byte) (bool, error) {
+	return func(key string, data []byte) (bool, error) {
+		backoff := c.config.EthClient.RateLimitBackoff
+		if backoff <= 0 {
+			backoff = time.Second
+		}
+		maxAttempts := c.config.Crawler.RateLimitMaxAttempts
+		if maxAttempts <= 0 {
+			maxAttempts = 1
+		}
+		for attempt := 1; ; attempt++ {
+			ack, err := process(key, data)
+			if !errors.Is(err, pkgErrors.ErrRateLimited) || attempt >= maxAttempts {
+				return ack, err
+			}
+			c.logger.Warn().Err(err).Str("message_key", key).Int("attempt", attempt).Dur("backoff", backoff).Msg("node quota exhausted, back off")
+			timer := time.NewTimer(backoff)
+			select {
+			case <-c.done:
+				timer.Stop()
+				return ack, err
+			case <-timer.C:
+			}
+			if backoff < _MaxRateLimitBackoff {
+				backoff *= 2
+			}
+		}
+	}
+}
+
+// _DetectReorg walks back from the crawled block until the stored parent hash chain agrees with the node,
+// and returns the stored blocks which are no longer on the canonical chain
+func (c *Crawler) _DetectReorg(ctx context.Context, block *types.Block) ([]*model.OrphanedBlock, error) {
+	orphaned := []*model.OrphanedBlock{}
+
+	stored, err := c.storageSvc.GetBlockHeader(ctx, model.GormBigInt(*block.Number()))
+	if err != nil && !errors.Is(err, pkgErrors.ErrResourceNotFound) {
+		return nil, err
+	}
+	if err == nil && stored.BlockHash != block.Hash().Hex() {
+		orphaned = append(orphaned, &model.OrphanedBlock{
+			BlockNumber: stored.BlockNumber,
+			BlockHash:   stored.BlockHash,
+			ReplacedBy:  block.Hash().Hex(),
+		})
+	}
+
+	number := new(big.Int).Set(block.Number())
+	parentHash := block.ParentHash()
+	for depth := 0; number.Sign() > 0; depth++ {
+		number = new(big.Int).Sub(number, big.NewInt(1))
+		stored, err := c.storageSvc.GetBlockHeader(ctx, model.GormBigInt(*number))
+		if errors.Is(err, pkgErrors.ErrResourceNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if stored.BlockHash == parentHash.Hex() {
+			break
+		}
+		if depth >= c.config.Crawler.MaxReorgDepth {
+			c.logger.Error().Int64("block_number", block.Number().Int64()).Int64("unresolved_block_number", number.Int64()).Int("max_reorg_depth", c.config.Crawler.MaxReorgDepth).Msg("reorg deeper than max reorg depth, rolled back partially")
+			break
+		}
+		orphaned = append(orphaned, &model.OrphanedBlock{
+			BlockNumber: stored.BlockNumber,
+			BlockHash:   stored.BlockHash,
+			ReplacedBy:  parentHash.Hex(),
+		})
+
+		canonical, err := c.crawler.GetBlockByNumber(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		parentHash = canonical.ParentHash()
+	}
+
+	return orphaned, nil
+}
+
+// _Rollback archives the orphaned blocks and pushes them back to the crawler
+func (c *Crawler) _Rollback(ctx context.Context, block *types.Block, orphaned []*model.OrphanedBlock, crawlerMessage model.CrawlerMessage) error {
+	c.logger.Warn().Int64("block_number", block.Number().Int64()).Int("depth", len(orphaned)).Msg("chain reorganization detected")
+	detectedAt := time.Now().UTC()
+	for _, orphanedBlock := range orphaned {
+		orphanedBlock.Depth = len(orphaned)
+		orphanedBlock.DetectedAt = detectedAt
+	}
+	if err := c.storageSvc.OrphanBlocks(ctx, orphaned...); err != nil {
+		return err
+	}
+
+	for _, orphanedBlock := range orphaned {
+		number := orphanedBlock.BlockNumber
+		if number.BigInt().Cmp(block.Number()) == 0 {
+			continue // the crawled block will be rewritten
+		}
+		messageBytes, err := model.MarshalCrawlerMessage(model.MessageEncoding(c.config.MQ.MessageEncoding), model.CrawlerMessage{
+			IsStable:    crawlerMessage.IsStable,
+			BlockNumber: number,
+			Finality:    crawlerMessage.Finality,
+		})
+		if err != nil {
+			return err
+		}
+		if err := c.mq.Publish(c.config.Crawler.Topic, model.MessageKey(number.BigInt()), messageBytes); err != nil {
+			return err
+		}
+		c.logger.Info().Int64("block_number", number.Int64()).Msg("push orphaned block to crawler")
+	}
+	return nil
+}
+
+func _GormBigIntPtr(bi *big.Int) *model.GormBigInt {
+	if bi == nil {
+		return nil
+	}
+	gbi := model.GormBigInt(*bi)
+	return &gbi
+}
+
+func _MarshalNotEmpty(v interface{}, length int) (model.JSON, error) {
+	if length == 0 {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// Stop stops backing off the messages, the queue, database and node client are left open
+func (c *Crawler) Stop() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
 func (c *Crawler) Shutdown() error {
+	c.Stop()
 	if err := c.mq.Close(); err != nil {
 		return err
 	}
@@ -137,3 +402,19 @@ func (c *Crawler) Shutdown() error {
 	c.crawler.Close()
 	return nil
 }
+
+// _Offload writes a message above the threshold to the blob store and returns the reference to publish instead
+func (c *Crawler) _Offload(ctx context.Context, number *big.Int, hash string, txCount int, b []byte) ([]byte, bool, error) {
+	if c.blobStore == nil || c.config.Blob.Threshold <= 0 || len(b) <= c.config.Blob.Threshold {
+		return b, false, nil
+	}
+	key := model.BlobKey(number, hash)
+	if err := c.blobStore.Put(ctx, key, b); err != nil {
+		return nil, false, err
+	}
+	reference, err := model.MarshalBlockReference(model.BlobReference{Key: key, Size: uint64(len(b)), TxCount: &txCount})
+	if err != nil {
+		return nil, false, err
+	}
+	return reference, true, nil
+}
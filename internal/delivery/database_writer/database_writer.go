@@ -2,21 +2,23 @@ package database_writer
 
 import (
 	"context"
-	"encoding/json"
 	"sync-ethereum/internal/config"
 	"sync-ethereum/internal/model"
 	"sync-ethereum/internal/service"
+	"sync-ethereum/pkg/blob"
 	"sync-ethereum/pkg/mq"
 
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
 
-func NewDatabaseWriter(config config.Config, logger zerolog.Logger, mq mq.MQ, storageSvc service.StorageService) *DatabaseWriter {
+func NewDatabaseWriter(config config.Config, logger zerolog.Logger, mq mq.MQ, storageSvc service.StorageService, blobStore blob.Store) *DatabaseWriter {
 	return &DatabaseWriter{
 		config:     config,
 		logger:     logger,
 		mq:         mq,
 		storageSvc: storageSvc,
+		blobStore:  blobStore,
 	}
 }
 
@@ -25,27 +27,84 @@ type DatabaseWriter struct {
 	logger     zerolog.Logger
 	mq         mq.MQ
 	storageSvc service.StorageService
+	blobStore  blob.Store
 }
 
 func (w *DatabaseWriter) Start() error {
 	return w.mq.Subscribe(context.Background(), w.config.DatabaseWriter.PoolSize, w.config.DatabaseWriter.Topic, func(key string, data []byte) (bool, error) {
 		ctx, cancel := context.WithTimeout(context.Background(), w.config.DatabaseWriter.Timeout)
 		defer cancel()
-		block := model.Block{}
-		err := json.Unmarshal(data, &block)
+		block, reference, err := model.UnmarshalBlock(data)
 		if err != nil {
+			if errors.Is(err, model.ErrUnsupportedEnvelopeVersion) {
+				return false, errors.WithMessage(mq.ErrUnprocessable, err.Error()) // a newer message is left to an upgraded writer
+			}
 			return true, err
 		}
+		if reference != nil {
+			block, err = w._ResolveBlock(ctx, *reference)
+			if err != nil {
+				// a missing blob is deleted by a previous delivery after the block is written
+				return errors.Is(err, blob.ErrNotFound) && w._Written(ctx, *reference), err
+			}
+		}
 		err = w.storageSvc.CreateBlock(ctx, &block)
 		if err != nil {
 			return false, err
 		}
+		if reference != nil {
+			if err := w.blobStore.Delete(ctx, reference.Key); err != nil {
+				w.logger.Error().Str("blob_key", reference.Key).Err(err).Msg("delete blob error")
+			}
+		}
 		return true, nil
 	}, func(key string, e error) {
 		w.logger.Error().Str("message_key", key).Err(e).Msg("DatabaseWriter error")
 	})
 }
 
+// _ResolveBlock fetches the block offloaded to the blob store
+func (w *DatabaseWriter) _ResolveBlock(ctx context.Context, reference model.BlobReference) (model.Block, error) {
+	if w.blobStore == nil {
+		return model.Block{}, errors.Errorf("no blob store configured for the reference, blob_key: %s", reference.Key)
+	}
+	data, err := w.blobStore.Get(ctx, reference.Key)
+	if err != nil {
+		return model.Block{}, errors.WithMessagef(err, "get blob error, blob_key: %s", reference.Key)
+	}
+	block, nested, err := model.UnmarshalBlock(data)
+	if err != nil {
+		return model.Block{}, errors.WithMessagef(err, "decode blob error, blob_key: %s", reference.Key)
+	}
+	if nested != nil {
+		return model.Block{}, errors.Errorf("blob is a reference itself, blob_key: %s", reference.Key)
+	}
+	return block, nil
+}
+
+// _Written tells the block of the reference is stored with all its transactions,
+// the header alone is pre-written by the crawler, and the block hash in the key tells a reorged block apart
+func (w *DatabaseWriter) _Written(ctx context.Context, reference model.BlobReference) bool {
+	if reference.TxCount == nil {
+		return false // the reference of an older crawler can't tell the pre-written header apart
+	}
+	number, hash, ok := model.ParseBlobKey(reference.Key)
+	if !ok {
+		return false
+	}
+	summaries, err := w.storageSvc.ListBlockSummary(ctx, model.BlockRange{From: number, To: number})
+	if err != nil {
+		w.logger.Error().Str("blob_key", reference.Key).Err(err).Msg("get block of missing blob error")
+		return false
+	}
+	for _, summary := range summaries {
+		if summary.BlockHash == hash && summary.TxCount == *reference.TxCount {
+			return true
+		}
+	}
+	return false
+}
+
 func (w *DatabaseWriter) Shutdown() error {
 	if err := w.mq.Close(); err != nil {
 		return err
@@ -0,0 +1,105 @@
+package dlq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync-ethereum/internal/config"
+	"sync-ethereum/pkg/mq"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func NewReplay(config config.Config, logger zerolog.Logger, mq mq.MQ) *Replay {
+	return &Replay{
+		config: config,
+		logger: logger,
+		mq:     mq,
+	}
+}
+
+// Option stops the replay after no dead letter is received for Idle, it runs until shutdown when Idle is zero
+type Option struct {
+	Idle time.Duration
+}
+
+// Replay publishes the dead letters back to the topics they failed on
+type Replay struct {
+	config config.Config
+	logger zerolog.Logger
+	mq     mq.MQ
+
+	lock   sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (r *Replay) Start(option Option) error {
+	topic := r.config.MQ.DeadLetterTopic
+	if len(topic) == 0 {
+		return errors.New("dead letter topic is not configured")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.lock.Lock()
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	r.lock.Unlock()
+	defer close(r.done)
+	defer cancel()
+
+	var replayed int64
+	lastReceived := atomic.Value{}
+	lastReceived.Store(time.Now())
+	if option.Idle > 0 {
+		go func() {
+			tick := time.NewTicker(option.Idle / 10)
+			defer tick.Stop()
+			for {
+				select {
+				case <-tick.C:
+					if time.Since(lastReceived.Load().(time.Time)) >= option.Idle {
+						r.logger.Info().Dur("idle", option.Idle).Msg("no more dead letter")
+						cancel()
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	r.logger.Info().Str("topic", topic).Msg("replay dead letters")
+	err := r.mq.Subscribe(ctx, 1, topic, func(key string, data []byte) (bool, error) {
+		lastReceived.Store(time.Now())
+		letter := mq.DeadLetter{}
+		if err := json.Unmarshal(data, &letter); err != nil {
+			return true, err // format error, not retry
+		}
+		if err := r.mq.Publish(letter.Topic, letter.Key, letter.Data); err != nil {
+			return false, err
+		}
+		atomic.AddInt64(&replayed, 1)
+		r.logger.Info().Str("topic", letter.Topic).Str("message_key", letter.Key).Int("attempts", letter.Attempts).Str("error", letter.Error).Msg("replay dead letter")
+		return true, nil
+	}, func(key string, e error) {
+		r.logger.Error().Str("message_key", key).Err(e).Msg("replay error")
+	})
+	r.logger.Info().Int64("replayed", atomic.LoadInt64(&replayed)).Msg("replay complete")
+	return err
+}
+
+func (r *Replay) Shutdown() error {
+	r.lock.Lock()
+	cancel, done := r.cancel, r.done
+	r.lock.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+	return r.mq.Close()
+}
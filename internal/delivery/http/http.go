@@ -2,7 +2,6 @@ package http
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"math/big"
 	"net/http"
@@ -52,7 +51,13 @@ func (server *HttpServer) setRouter() {
 		apiV1 := server.engine.Group("/api/v1")
 		apiV1.GET("/blocks", server.GetBlocks)
 		apiV1.GET("/blocks/:id", server.GetBlock)
+		apiV1.GET("/blocks/:id/uncles", server.GetBlockUncles)
+		apiV1.GET("/blocks/:id/withdrawals", server.GetBlockWithdrawals)
 		apiV1.GET("/transaction/:txhash", server.GetTransation)
+		apiV1.GET("/reorgs", server.GetReorgs)
+		apiV1.GET("/logs", server.GetLogs)
+		apiV1.GET("/addresses/:address/transactions", server.GetAddressTransactions)
+		apiV1.GET("/addresses/:address/withdrawals", server.GetAddressWithdrawals)
 	}
 }
 
@@ -77,8 +82,16 @@ func (server *HttpServer) Run(addr string) error {
 	return server.httpServer.ListenAndServe()
 }
 
+// Stop closes the http server, the queue and database are left open
+func (server *HttpServer) Stop() error {
+	if server.httpServer != nil { // nil before started
+		return server.httpServer.Close()
+	}
+	return nil
+}
+
 func (server *HttpServer) Shutdown() error {
-	if err := server.httpServer.Close(); err != nil {
+	if err := server.Stop(); err != nil {
 		return err
 	}
 	if err := server.mq.Close(); err != nil {
@@ -127,23 +140,32 @@ func (server *HttpServer) GetBlocks(ctx *gin.Context) {
 			BlockTime:   block.BlockTime,
 			ParentHash:  block.ParentHash,
 			IsStable:    block.IsStable,
+			Finality:    block.Finality,
 		}
 	}
 
 	ctx.JSON(http.StatusOK, GetBlocksResponse{respBlock})
 }
 
-func (server *HttpServer) GetBlock(ctx *gin.Context) {
+func (server *HttpServer) _ParseBlockNumber(ctx *gin.Context) (model.GormBigInt, bool) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
 		server.logger.Warn().Err(err).Msg("input param id is invalid")
 		ctx.AbortWithError(http.StatusBadRequest, err)
-		return
+		return model.GormBigInt{}, false
 	}
 	bigI := big.NewInt(id)
+	return model.GormBigInt(*bigI), true
+}
+
+func (server *HttpServer) GetBlock(ctx *gin.Context) {
+	blockNumber, ok := server._ParseBlockNumber(ctx)
+	if !ok {
+		return
+	}
 	block, err := server.storageSvc.GetBlock(ctx, model.Block{
-		BlockNumber: model.GormBigInt(*bigI),
+		BlockNumber: blockNumber,
 	})
 	if err != nil {
 		if errors.Is(err, pkgErrors.ErrResourceNotFound) {
@@ -163,6 +185,10 @@ func (server *HttpServer) GetBlock(ctx *gin.Context) {
 	for i, tx := range block.Transaction {
 		transactions[i] = tx.TXHash
 	}
+	uncles := make([]string, len(block.Uncles))
+	for i, uncle := range block.Uncles {
+		uncles[i] = uncle.UncleHash
+	}
 
 	ctx.JSON(http.StatusOK, GetBlockResponse{
 		BlockNumber:  block.BlockNumber,
@@ -170,32 +196,143 @@ func (server *HttpServer) GetBlock(ctx *gin.Context) {
 		BlockTime:    block.BlockTime,
 		ParentHash:   block.ParentHash,
 		IsStable:     block.IsStable,
+		Finality:     block.Finality,
+		Coinbase:     block.Coinbase,
+		GasLimit:     block.GasLimit,
+		GasUsed:      block.GasUsed,
+		BaseFee:      block.BaseFee,
+		Difficulty:   block.Difficulty,
+		Nonce:        block.Nonce,
+		MixDigest:    block.MixDigest,
+		StateRoot:    block.StateRoot,
+		TxRoot:       block.TxRoot,
+		ReceiptRoot:  block.ReceiptRoot,
+		ExtraData:    block.ExtraData,
+		Size:         block.Size,
+		UncleCount:   block.UncleCount,
+		Uncles:       uncles,
 		Transactions: transactions,
 	})
 }
 
-func (server *HttpServer) _Compensate(ctx context.Context, block model.Block) {
-	currentBlock, err := server.storageSvc.GetCurrentBlockNumber(ctx)
+func (server *HttpServer) GetBlockUncles(ctx *gin.Context) {
+	blockNumber, ok := server._ParseBlockNumber(ctx)
+	if !ok {
+		return
+	}
+	modelUncles, err := server.storageSvc.ListUncle(ctx, blockNumber)
 	if err != nil {
-		server.logger.Error().Err(err).Msg("get current block number error")
+		ctx.AbortWithError(http.StatusInternalServerError, err)
+		server.logger.Error().Err(err).Msg("list uncle error")
 		return
 	}
-	if block.BlockNumber.Int64() < (currentBlock.Int64() - int64(server.config.Scheduler.UnstableNumber)) {
-		message := model.CrawlerMessage{
-			IsStable:    true,
-			BlockNumber: block.BlockNumber,
+
+	uncles := make([]Uncle, len(modelUncles))
+	for i, uncle := range modelUncles {
+		uncles[i] = Uncle{
+			Position:    uncle.Position,
+			UncleHash:   uncle.UncleHash,
+			UncleNumber: uncle.UncleNumber,
+			ParentHash:  uncle.ParentHash,
+			Coinbase:    uncle.Coinbase,
+			Difficulty:  uncle.Difficulty,
+			GasLimit:    uncle.GasLimit,
+			GasUsed:     uncle.GasUsed,
+			UncleTime:   uncle.UncleTime,
+			ExtraData:   uncle.ExtraData,
 		}
-		messageBytes, err := json.Marshal(message)
+	}
+
+	ctx.JSON(http.StatusOK, GetBlockUnclesResponse{uncles})
+}
+
+func (server *HttpServer) _Compensate(ctx context.Context, block model.Block) {
+	message := model.CrawlerMessage{
+		IsStable:    true,
+		BlockNumber: block.BlockNumber,
+	}
+	if server.config.Scheduler.Stability == config.StabilityFinality {
+		_, finalized, err := server.storageSvc.GetFinality(ctx)
+		if err != nil {
+			server.logger.Error().Err(err).Msg("get finalized block number error")
+			return
+		}
+		if block.BlockNumber.BigInt().Cmp(finalized.BigInt()) > 0 {
+			return
+		}
+		message.Finality = model.FinalityFinalized
+	} else {
+		currentBlock, err := server.storageSvc.GetCurrentBlockNumber(ctx)
 		if err != nil {
-			server.logger.Error().Int64("block_number", block.BlockNumber.Int64()).Err(err).Msg("marshal crawler message error")
+			server.logger.Error().Err(err).Msg("get current block number error")
 			return
 		}
-		if err := server.mq.Publish(server.config.Crawler.Topic, uuid.New().String(), messageBytes); err != nil {
-			server.logger.Error().Int64("block_number", block.BlockNumber.Int64()).Err(err).Msg("push crawler id error")
+		if block.BlockNumber.Int64() >= (currentBlock.Int64() - int64(server.config.Scheduler.UnstableNumber)) {
 			return
 		}
-		server.logger.Info().Int64("block_number", block.BlockNumber.Int64()).Msg("compensate block")
 	}
+
+	messageBytes, err := model.MarshalCrawlerMessage(model.MessageEncoding(server.config.MQ.MessageEncoding), message)
+	if err != nil {
+		server.logger.Error().Int64("block_number", block.BlockNumber.Int64()).Err(err).Msg("marshal crawler message error")
+		return
+	}
+	if err := server.mq.Publish(server.config.Crawler.Topic, model.MessageKey(block.BlockNumber.BigInt()), messageBytes); err != nil {
+		server.logger.Error().Int64("block_number", block.BlockNumber.Int64()).Err(err).Msg("push crawler id error")
+		return
+	}
+	server.logger.Info().Int64("block_number", block.BlockNumber.Int64()).Msg("compensate block")
+}
+
+func (server *HttpServer) GetReorgs(ctx *gin.Context) {
+	limit := 10
+	if limitStr := ctx.Query("limit"); len(limitStr) > 0 {
+		if convLimit, err := strconv.Atoi(limitStr); err != nil {
+			server.logger.Warn().Err(err).Msg("input param limit is invalid")
+		} else {
+			limit = convLimit
+		}
+	}
+	page := 1
+	if pageStr := ctx.Query("page"); len(pageStr) > 0 {
+		convPage, err := strconv.Atoi(pageStr)
+		if err != nil {
+			server.logger.Warn().Err(err).Msg("input param page is invalid")
+			ctx.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+		page = convPage
+	}
+
+	orphanedBlocks, err := server.storageSvc.ListOrphanedBlock(ctx, model.OrphanedBlock{}, model.Pagination{
+		Page:    int64(page),
+		PerPage: int64(limit),
+	}, model.Sorting([]model.SortField{{Field: "detected_at", Order: model.SortDESC}, {Field: "id", Order: model.SortDESC}}))
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, err)
+		server.logger.Error().Err(err).Msg("list orphaned block error")
+		return
+	}
+
+	reorgs := make([]Reorg, len(orphanedBlocks))
+	for i, orphanedBlock := range orphanedBlocks {
+		transactions := make([]string, len(orphanedBlock.Block.Transaction))
+		for j, tx := range orphanedBlock.Block.Transaction {
+			transactions[j] = tx.TXHash
+		}
+		reorgs[i] = Reorg{
+			BlockNumber:  orphanedBlock.BlockNumber,
+			BlockHash:    orphanedBlock.BlockHash,
+			BlockTime:    orphanedBlock.BlockTime,
+			ParentHash:   orphanedBlock.ParentHash,
+			ReplacedBy:   orphanedBlock.ReplacedBy,
+			Depth:        orphanedBlock.Depth,
+			Transactions: transactions,
+			DetectedAt:   orphanedBlock.DetectedAt,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, GetReorgsResponse{reorgs})
 }
 
 func (server *HttpServer) GetTransation(ctx *gin.Context) {
@@ -217,18 +354,167 @@ func (server *HttpServer) GetTransation(ctx *gin.Context) {
 	logs := make([]TransactionLog, len(transaction.Logs))
 	for i, log := range transaction.Logs {
 		logs[i] = TransactionLog{
-			Index: log.Index,
-			Data:  log.Data,
+			BlockNumber: log.BlockNumber,
+			TxIndex:     log.TxIndex,
+			Index:       log.Index,
+			Address:     log.Address,
+			Topics:      log.Topics(),
+			Data:        log.Data,
+			Removed:     log.Removed,
 		}
 	}
 
 	ctx.JSON(http.StatusOK, GetTransactionResponse{
-		TXHash: transaction.TXHash,
-		From:   transaction.From,
-		To:     transaction.To,
-		Nonce:  transaction.Nonce,
-		Data:   transaction.Data,
-		Value:  transaction.Value,
-		Logs:   logs,
+		TXHash:            transaction.TXHash,
+		From:              transaction.From,
+		To:                transaction.To,
+		Nonce:             transaction.Nonce,
+		Data:              transaction.Data,
+		Value:             transaction.Value,
+		Type:              transaction.Type,
+		Gas:               transaction.Gas,
+		GasPrice:          transaction.GasPrice,
+		GasTipCap:         transaction.GasTipCap,
+		GasFeeCap:         transaction.GasFeeCap,
+		AccessList:        transaction.AccessList,
+		BlobGasFeeCap:     transaction.BlobGasFeeCap,
+		BlobHashes:        transaction.BlobHashes,
+		Status:            transaction.Status,
+		GasUsed:           transaction.GasUsed,
+		CumulativeGasUsed: transaction.CumulativeGasUsed,
+		EffectiveGasPrice: transaction.EffectiveGasPrice,
+		ContractAddress:   transaction.ContractAddress,
+		Logs:              logs,
 	})
 }
+
+func (server *HttpServer) GetLogs(ctx *gin.Context) {
+	req := GetLogsRequest{}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		server.logger.Warn().Err(err).Msg("input param is invalid")
+		ctx.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	filter, err := req.LogFilter()
+	if err != nil {
+		server.logger.Warn().Err(err).Msg("input param is invalid")
+		ctx.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	transactionLogs, err := server.storageSvc.ListLogs(ctx, filter, req.Pagination())
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, err)
+		server.logger.Error().Err(err).Msg("list logs error")
+		return
+	}
+
+	logs := make([]TransactionLog, len(transactionLogs))
+	for i, log := range transactionLogs {
+		logs[i] = TransactionLog{
+			TXHash:      log.TXHash,
+			BlockNumber: log.BlockNumber,
+			TxIndex:     log.TxIndex,
+			Index:       log.Index,
+			Address:     log.Address,
+			Topics:      log.Topics(),
+			Data:        log.Data,
+			Removed:     log.Removed,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, GetLogsResponse{logs})
+}
+
+func (server *HttpServer) GetAddressTransactions(ctx *gin.Context) {
+	req := GetAddressTransactionsRequest{}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		server.logger.Warn().Err(err).Msg("input param is invalid")
+		ctx.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	filter, err := req.AddressFilter(ctx.Param("address"))
+	if err != nil {
+		server.logger.Warn().Err(err).Msg("input param is invalid")
+		ctx.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	limit := req.GetLimit()
+	transactions, err := server.storageSvc.ListTransactionByAddress(ctx, filter, limit+1) // fetch one more to know if there is a next page
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, err)
+		server.logger.Error().Err(err).Msg("list address transactions error")
+		return
+	}
+
+	resp := GetAddressTransactionsResponse{}
+	if len(transactions) > limit {
+		transactions = transactions[:limit]
+		last := transactions[limit-1]
+		resp.NextCursor = _EncodeTransactionCursor(model.TransactionCursor{
+			BlockNumber: last.BlockNumber.BigInt(),
+			TxIndex:     last.TxIndex,
+			TXHash:      last.TXHash,
+		})
+	}
+	resp.Transactions = make([]AddressTransaction, len(transactions))
+	for i, transaction := range transactions {
+		direction := model.TransactionDirectionIn
+		if transaction.From == filter.Address {
+			direction = model.TransactionDirectionOut
+		}
+		resp.Transactions[i] = AddressTransaction{
+			TXHash:      transaction.TXHash,
+			BlockNumber: transaction.BlockNumber,
+			TxIndex:     transaction.TxIndex,
+			Direction:   string(direction),
+			From:        transaction.From,
+			To:          transaction.To,
+			Nonce:       transaction.Nonce,
+			Value:       transaction.Value,
+			Status:      transaction.Status,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (server *HttpServer) GetBlockWithdrawals(ctx *gin.Context) {
+	blockNumber, ok := server._ParseBlockNumber(ctx)
+	if !ok {
+		return
+	}
+	withdrawals, err := server.storageSvc.ListBlockWithdrawal(ctx, blockNumber)
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, err)
+		server.logger.Error().Err(err).Msg("list block withdrawals error")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, GetWithdrawalsResponse{_Withdrawals(withdrawals)})
+}
+
+func (server *HttpServer) GetAddressWithdrawals(ctx *gin.Context) {
+	req := GetAddressWithdrawalsRequest{}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		server.logger.Warn().Err(err).Msg("input param is invalid")
+		ctx.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	address, blockRange, err := req.Filter(ctx.Param("address"))
+	if err != nil {
+		server.logger.Warn().Err(err).Msg("input param is invalid")
+		ctx.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	withdrawals, err := server.storageSvc.ListAddressWithdrawal(ctx, address, blockRange, req.Pagination())
+	if err != nil {
+		ctx.AbortWithError(http.StatusInternalServerError, err)
+		server.logger.Error().Err(err).Msg("list address withdrawals error")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, GetWithdrawalsResponse{_Withdrawals(withdrawals)})
+}
@@ -1,6 +1,21 @@
 package http
 
-import "sync-ethereum/internal/model"
+import (
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync-ethereum/internal/model"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	_DefaultPerPage = 100
+	_MaxPerPage     = 1000
+)
 
 type GetBlocksResponse struct {
 	Blocks []Block `json:"block"`
@@ -12,28 +27,329 @@ type Block struct {
 	BlockTime   uint64           `json:"block_time"`
 	ParentHash  string           `json:"parent_hash"`
 	IsStable    bool             `json:"is_stable"`
+	Finality    model.Finality   `json:"finality,omitempty"`
 }
 
 type GetBlockResponse struct {
+	BlockNumber  model.GormBigInt  `json:"block_num"`
+	BlockHash    string            `json:"block_hash"`
+	BlockTime    uint64            `json:"block_time"`
+	ParentHash   string            `json:"parent_hash"`
+	IsStable     bool              `json:"is_stable"`
+	Finality     model.Finality    `json:"finality,omitempty"`
+	Coinbase     string            `json:"coinbase"`
+	GasLimit     uint64            `json:"gas_limit"`
+	GasUsed      uint64            `json:"gas_used"`
+	BaseFee      *model.GormBigInt `json:"base_fee"`
+	Difficulty   model.GormBigInt  `json:"difficulty"`
+	Nonce        uint64            `json:"nonce"`
+	MixDigest    string            `json:"mix_digest"`
+	StateRoot    string            `json:"state_root"`
+	TxRoot       string            `json:"tx_root"`
+	ReceiptRoot  string            `json:"receipt_root"`
+	ExtraData    model.BlockData   `json:"extra_data"`
+	Size         uint64            `json:"size"`
+	UncleCount   int               `json:"uncle_count"`
+	Uncles       []string          `json:"uncles"`
+	Transactions []string          `json:"transactions"`
+}
+
+type GetBlockUnclesResponse struct {
+	Uncles []Uncle `json:"uncles"`
+}
+
+type Uncle struct {
+	Position    int              `json:"position"`
+	UncleHash   string           `json:"uncle_hash"`
+	UncleNumber model.GormBigInt `json:"uncle_num"`
+	ParentHash  string           `json:"parent_hash"`
+	Coinbase    string           `json:"coinbase"`
+	Difficulty  model.GormBigInt `json:"difficulty"`
+	GasLimit    uint64           `json:"gas_limit"`
+	GasUsed     uint64           `json:"gas_used"`
+	UncleTime   uint64           `json:"uncle_time"`
+	ExtraData   model.BlockData  `json:"extra_data"`
+}
+
+type GetTransactionResponse struct {
+	TXHash            string            `json:"tx_hash"`
+	From              string            `json:"from"`
+	To                string            `json:"to"`
+	Nonce             uint64            `json:"nonce"`
+	Data              model.BlockData   `json:"data"`
+	Value             model.GormBigInt  `json:"value"`
+	Type              uint8             `json:"type"`
+	Gas               uint64            `json:"gas"`
+	GasPrice          model.GormBigInt  `json:"gas_price"`
+	GasTipCap         *model.GormBigInt `json:"gas_tip_cap,omitempty"`
+	GasFeeCap         *model.GormBigInt `json:"gas_fee_cap,omitempty"`
+	AccessList        model.JSON        `json:"access_list,omitempty"`
+	BlobGasFeeCap     *model.GormBigInt `json:"blob_gas_fee_cap,omitempty"`
+	BlobHashes        model.JSON        `json:"blob_hashes,omitempty"`
+	Status            uint64            `json:"status"`
+	GasUsed           uint64            `json:"gas_used"`
+	CumulativeGasUsed uint64            `json:"cumulative_gas_used"`
+	EffectiveGasPrice model.GormBigInt  `json:"effective_gas_price"`
+	ContractAddress   string            `json:"contract_address"`
+	Logs              []TransactionLog  `json:"logs"`
+}
+
+type TransactionLog struct {
+	TXHash      string           `json:"tx_hash,omitempty"`
+	BlockNumber model.GormBigInt `json:"block_num"`
+	TxIndex     uint             `json:"tx_index"`
+	Index       uint64           `json:"index"`
+	Address     string           `json:"address"`
+	Topics      []string         `json:"topics"`
+	Data        model.BlockData  `json:"data"`
+	Removed     bool             `json:"removed"`
+}
+
+type GetReorgsResponse struct {
+	Reorgs []Reorg `json:"reorgs"`
+}
+
+type Reorg struct {
 	BlockNumber  model.GormBigInt `json:"block_num"`
 	BlockHash    string           `json:"block_hash"`
 	BlockTime    uint64           `json:"block_time"`
 	ParentHash   string           `json:"parent_hash"`
-	IsStable     bool             `json:"is_stable"`
+	ReplacedBy   string           `json:"replaced_by"`
+	Depth        int              `json:"depth"`
 	Transactions []string         `json:"transactions"`
+	DetectedAt   time.Time        `json:"detected_at"`
 }
 
-type GetTransactionResponse struct {
-	TXHash string           `json:"tx_hash"`
-	From   string           `json:"from"`
-	To     string           `json:"to"`
-	Nonce  uint64           `json:"nonce"`
-	Data   model.BlockData  `json:"data"`
-	Value  model.GormBigInt `json:"value"`
-	Logs   []TransactionLog `json:"logs"`
+type GetLogsRequest struct {
+	FromBlock *int64   `form:"from_block"`
+	ToBlock   *int64   `form:"to_block"`
+	Address   []string `form:"address"`
+	Topic0    []string `form:"topic0"`
+	Topic1    []string `form:"topic1"`
+	Topic2    []string `form:"topic2"`
+	Topic3    []string `form:"topic3"`
+	Page      int64    `form:"page"`
+	PerPage   int64    `form:"per_page"`
 }
 
-type TransactionLog struct {
-	Index uint64          `json:"index"`
-	Data  model.BlockData `json:"data"`
+// LogFilter validates the request, values of a param can be repeated or separated by comma
+func (req GetLogsRequest) LogFilter() (model.LogFilter, error) {
+	blockRange, err := _BlockRange(req.FromBlock, req.ToBlock)
+	if err != nil {
+		return model.LogFilter{}, err
+	}
+	filter := model.LogFilter{BlockRange: blockRange}
+
+	for _, address := range _SplitValues(req.Address) {
+		if !common.IsHexAddress(address) {
+			return filter, fmt.Errorf("invalid address [%s]", address)
+		}
+		filter.Addresses = append(filter.Addresses, common.HexToAddress(address).Hex())
+	}
+
+	filter.Topics = make([][]string, 4)
+	for i, values := range [][]string{req.Topic0, req.Topic1, req.Topic2, req.Topic3} {
+		for _, topic := range _SplitValues(values) {
+			if len(common.FromHex(topic)) != common.HashLength {
+				return filter, fmt.Errorf("invalid topic%d [%s]", i, topic)
+			}
+			filter.Topics[i] = append(filter.Topics[i], common.HexToHash(topic).Hex())
+		}
+	}
+	return filter, nil
+}
+
+func (req GetLogsRequest) Pagination() model.Pagination {
+	return _Pagination(req.Page, req.PerPage)
+}
+
+func _BlockRange(fromBlock, toBlock *int64) (model.BlockRange, error) {
+	blockRange := model.BlockRange{}
+	if fromBlock != nil {
+		blockRange.From = big.NewInt(*fromBlock)
+	}
+	if toBlock != nil {
+		blockRange.To = big.NewInt(*toBlock)
+	}
+	if blockRange.From != nil && blockRange.To != nil && blockRange.From.Cmp(blockRange.To) > 0 {
+		return blockRange, fmt.Errorf("from_block %d is greater than to_block %d", *fromBlock, *toBlock)
+	}
+	return blockRange, nil
+}
+
+func _Pagination(page, perPage int64) model.Pagination {
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = _DefaultPerPage
+	}
+	if perPage > _MaxPerPage {
+		perPage = _MaxPerPage
+	}
+	return model.Pagination{
+		Page:    page,
+		PerPage: perPage,
+	}
+}
+
+func _SplitValues(values []string) []string {
+	result := []string{}
+	for _, value := range values {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); len(v) > 0 {
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
+
+type GetLogsResponse struct {
+	Logs []TransactionLog `json:"logs"`
+}
+
+type GetAddressTransactionsRequest struct {
+	FromBlock *int64 `form:"from_block"`
+	ToBlock   *int64 `form:"to_block"`
+	Direction string `form:"direction"`
+	Cursor    string `form:"cursor"`
+	Limit     int    `form:"limit"`
+}
+
+func (req GetAddressTransactionsRequest) AddressFilter(address string) (model.AddressFilter, error) {
+	if !common.IsHexAddress(address) {
+		return model.AddressFilter{}, fmt.Errorf("invalid address [%s]", address)
+	}
+	blockRange, err := _BlockRange(req.FromBlock, req.ToBlock)
+	if err != nil {
+		return model.AddressFilter{}, err
+	}
+
+	direction := model.TransactionDirection(strings.ToLower(req.Direction))
+	switch direction {
+	case "":
+		direction = model.TransactionDirectionAll
+	case model.TransactionDirectionIn, model.TransactionDirectionOut, model.TransactionDirectionAll:
+	default:
+		return model.AddressFilter{}, fmt.Errorf("invalid direction [%s]", req.Direction)
+	}
+
+	filter := model.AddressFilter{
+		BlockRange: blockRange,
+		Address:    common.HexToAddress(address).Hex(),
+		Direction:  direction,
+	}
+	if len(req.Cursor) > 0 {
+		cursor, err := _DecodeTransactionCursor(req.Cursor)
+		if err != nil {
+			return model.AddressFilter{}, err
+		}
+		filter.Cursor = &cursor
+	}
+	return filter, nil
+}
+
+func (req GetAddressTransactionsRequest) GetLimit() int {
+	if req.Limit <= 0 {
+		return _DefaultPerPage
+	}
+	if req.Limit > _MaxPerPage {
+		return _MaxPerPage
+	}
+	return req.Limit
+}
+
+func _EncodeTransactionCursor(cursor model.TransactionCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%d:%s", cursor.BlockNumber.String(), cursor.TxIndex, cursor.TXHash)))
+}
+
+func _DecodeTransactionCursor(s string) (model.TransactionCursor, error) {
+	cursor := model.TransactionCursor{}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor, fmt.Errorf("invalid cursor [%s]", s)
+	}
+	values := strings.Split(string(b), ":")
+	if len(values) != 3 {
+		return cursor, fmt.Errorf("invalid cursor [%s]", s)
+	}
+	number, ok := new(big.Int).SetString(values[0], 10)
+	if !ok {
+		return cursor, fmt.Errorf("invalid cursor [%s]", s)
+	}
+	txIndex, err := strconv.ParseUint(values[1], 10, 64)
+	if err != nil {
+		return cursor, fmt.Errorf("invalid cursor [%s]", s)
+	}
+	cursor.BlockNumber = number
+	cursor.TxIndex = uint(txIndex)
+	cursor.TXHash = values[2]
+	return cursor, nil
+}
+
+type GetAddressTransactionsResponse struct {
+	Transactions []AddressTransaction `json:"transactions"`
+	NextCursor   string               `json:"next_cursor,omitempty"`
+}
+
+type AddressTransaction struct {
+	TXHash      string           `json:"tx_hash"`
+	BlockNumber model.GormBigInt `json:"block_num"`
+	TxIndex     uint             `json:"tx_index"`
+	Direction   string           `json:"direction"`
+	From        string           `json:"from"`
+	To          string           `json:"to"`
+	Nonce       uint64           `json:"nonce"`
+	Value       model.GormBigInt `json:"value"`
+	Status      uint64           `json:"status"`
+}
+
+type Withdrawal struct {
+	BlockNumber    model.GormBigInt `json:"block_num"`
+	Index          uint64           `json:"index"`
+	ValidatorIndex uint64           `json:"validator_index"`
+	Address        string           `json:"address"`
+	Amount         uint64           `json:"amount"` // gwei
+}
+
+type GetWithdrawalsResponse struct {
+	Withdrawals []Withdrawal `json:"withdrawals"`
+}
+
+type GetAddressWithdrawalsRequest struct {
+	FromBlock *int64 `form:"from_block"`
+	ToBlock   *int64 `form:"to_block"`
+	Page      int64  `form:"page"`
+	PerPage   int64  `form:"per_page"`
+}
+
+// Filter validates the request and returns the checksummed address with the block range
+func (req GetAddressWithdrawalsRequest) Filter(address string) (string, model.BlockRange, error) {
+	if !common.IsHexAddress(address) {
+		return "", model.BlockRange{}, fmt.Errorf("invalid address [%s]", address)
+	}
+	blockRange, err := _BlockRange(req.FromBlock, req.ToBlock)
+	if err != nil {
+		return "", model.BlockRange{}, err
+	}
+	return common.HexToAddress(address).Hex(), blockRange, nil
+}
+
+func (req GetAddressWithdrawalsRequest) Pagination() model.Pagination {
+	return _Pagination(req.Page, req.PerPage)
+}
+
+func _Withdrawals(modelWithdrawals []model.Withdrawal) []Withdrawal {
+	withdrawals := make([]Withdrawal, len(modelWithdrawals))
+	for i, withdrawal := range modelWithdrawals {
+		withdrawals[i] = Withdrawal{
+			BlockNumber:    withdrawal.BlockNumber,
+			Index:          withdrawal.Index,
+			ValidatorIndex: withdrawal.ValidatorIndex,
+			Address:        withdrawal.Address,
+			Amount:         withdrawal.Amount,
+		}
+	}
+	return withdrawals
 }
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"sync-ethereum/internal/model"
+)
+
+func TestTransactionCursor(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	tests := []struct {
+		name   string
+		cursor model.TransactionCursor
+	}{
+		{"genesis", model.TransactionCursor{BlockNumber: big.NewInt(0), TxIndex: 0, TXHash: "0x00"}},
+		{"block", model.TransactionCursor{BlockNumber: big.NewInt(17034870), TxIndex: 42, TXHash: "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060"}},
+		{"beyond int64", model.TransactionCursor{BlockNumber: huge, TxIndex: 1, TXHash: "0x01"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := _DecodeTransactionCursor(_EncodeTransactionCursor(tt.cursor))
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got.BlockNumber.Cmp(tt.cursor.BlockNumber) != 0 || got.TxIndex != tt.cursor.TxIndex || got.TXHash != tt.cursor.TXHash {
+				t.Fatalf("got %v:%d:%s, want %v:%d:%s", got.BlockNumber, got.TxIndex, got.TXHash, tt.cursor.BlockNumber, tt.cursor.TxIndex, tt.cursor.TXHash)
+			}
+		})
+	}
+}
+
+func TestDecodeTransactionCursorInvalid(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "!!!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte("1:2:0x01"))},
+		{"missing field", encode("1:2")},
+		{"extra field", encode("1:2:0x01:3")},
+		{"invalid block number", encode("0x1:2:0x01")},
+		{"negative tx index", encode("1:-2:0x01")},
+		{"invalid tx index", encode("1:a:0x01")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := _DecodeTransactionCursor(tt.cursor); err == nil {
+				t.Fatalf("decode [%s] succeeded, want error", tt.cursor)
+			}
+		})
+	}
+}
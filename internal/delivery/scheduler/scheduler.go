@@ -2,7 +2,6 @@ package scheduler
 
 import (
 	"context"
-	"encoding/json"
 	"math/big"
 	"sync-ethereum/internal/config"
 	"sync-ethereum/internal/model"
@@ -10,10 +9,13 @@ import (
 	"sync-ethereum/pkg/mq"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/rs/zerolog"
 )
 
+const _HeadBufferSize = 16
+
 func NewScheduler(config config.Config, logger zerolog.Logger, mq mq.MQ, crawler service.CrawlerService, storageSvc service.StorageService) *Scheduler {
 	return &Scheduler{
 		config:     config,
@@ -38,81 +40,182 @@ func (scheduler *Scheduler) Start() error {
 	scheduler.close = func() {
 		close(done)
 	}
+
+	heads := make(chan *types.Header, _HeadBufferSize)
+	var sub ethereum.Subscription
+	var subErr <-chan error
+	subscribe := func() {
+		if scheduler.config.Scheduler.Sync.Mode != config.SyncModeSubscribe || sub != nil {
+			return
+		}
+		s, err := scheduler.crawler.SubscribeNewHead(context.Background(), heads)
+		if err != nil {
+			scheduler.logger.Error().Err(err).Msg("subscribe new heads error, fall back to polling")
+			return
+		}
+		scheduler.logger.Info().Msg("new heads subscribed")
+		sub, subErr = s, s.Err()
+	}
+	subscribe()
+
 	tick := time.NewTicker(scheduler.config.Scheduler.Sync.Interval)
+	defer tick.Stop()
 	for {
 		select {
 		case <-tick.C:
-			ctx, cancel := context.WithTimeout(context.Background(), scheduler.config.Scheduler.Sync.Interval)
-
-			number, err := scheduler.crawler.GetBlockNumber(ctx)
-			if err != nil {
-				scheduler.logger.Error().Err(err).Msg("parse current block number error")
-				cancel()
-				continue
-			}
-			scheduler.logger.Info().Msgf("parse current block number: %d", number.Int64())
-			onlineBockNumber := model.GormBigInt(*number)
-
-			currentBlockNumber, err := scheduler.storageSvc.GetCurrentBlockNumber(ctx)
-			if err != nil {
-				scheduler.logger.Error().Err(err).Msg("get database current block number error")
-				cancel()
-				continue
+			scheduler._Sync(nil)
+			subscribe()
+		case head := <-heads:
+			scheduler.logger.Debug().Int64("block_number", head.Number.Int64()).Msg("receive new head")
+			scheduler._Sync(head.Number)
+		case err := <-subErr:
+			scheduler.logger.Warn().Err(err).Msg("new heads subscription dropped, fall back to polling")
+			sub.Unsubscribe()
+			sub, subErr = nil, nil
+		case <-done:
+			if sub != nil {
+				sub.Unsubscribe()
 			}
+			return nil
+		}
+	}
+}
 
-			if currentBlockNumber.Int64() < scheduler.config.Scheduler.StartAt {
-				bi := big.NewInt(scheduler.config.Scheduler.StartAt)
-				currentBlockNumber = model.GormBigInt(*bi)
+// _Sync pushes the blocks from the current block number up to the head, the head is fetched from the node when number is nil
+func (scheduler *Scheduler) _Sync(number *big.Int) {
+	ctx, cancel := context.WithTimeout(context.Background(), scheduler.config.Scheduler.Sync.Interval)
 
-				err = scheduler.storageSvc.UpdateCurrentBlockNumber(ctx, currentBlockNumber, onlineBockNumber)
-				if err != nil {
-					scheduler.logger.Error().Int64("block_number", currentBlockNumber.Int64()).Err(err).Msg("update db current block number error")
-					cancel()
-					continue
-				}
-			}
+	if number == nil {
+		var err error
+		number, err = scheduler.crawler.GetBlockNumber(ctx)
+		if err != nil {
+			scheduler.logger.Error().Err(err).Msg("parse current block number error")
+			cancel()
+			return
+		}
+	}
+	scheduler.logger.Info().Msgf("parse current block number: %d", number.Int64())
+	onlineBockNumber := model.GormBigInt(*number)
 
-			i := currentBlockNumber.Int64() - int64(scheduler.config.Scheduler.UnstableNumber) // update unstable block
-			limit := i + scheduler.config.Scheduler.BatchLimit
-			for i <= number.Int64() && i < limit {
-				scheduler.logger.Info().Int64("block_number", i).Err(err).Msg("push crawler id")
-				n := big.NewInt(i)
-				isStable := true
-				if i <= onlineBockNumber.Int64() && i > (onlineBockNumber.Int64()-int64(scheduler.config.Scheduler.UnstableNumber)) {
-					isStable = false
-				}
-				message := model.CrawlerMessage{
-					IsStable:    isStable,
-					BlockNumber: model.GormBigInt(*n),
-				}
-				messageBytes, err := json.Marshal(message)
-				if err != nil {
-					scheduler.logger.Error().Int64("block_number", i).Err(err).Msg("marshal crawler message error")
-					continue
-				}
-				if err := scheduler.mq.Publish(scheduler.config.Crawler.Topic, uuid.New().String(), messageBytes); err != nil {
-					scheduler.logger.Error().Int64("block_number", i).Err(err).Msg("push crawler id error")
-					break
-				}
-				i++
-			}
-			number = big.NewInt(i - 1)
-			err = scheduler.storageSvc.UpdateCurrentBlockNumber(ctx, model.GormBigInt(*number), onlineBockNumber)
-			if err != nil {
-				scheduler.logger.Error().Int64("block_number", i).Err(err).Msg("update db current block number error")
-				cancel()
-				continue
-			}
+	currentBlockNumber, err := scheduler.storageSvc.GetCurrentBlockNumber(ctx)
+	if err != nil {
+		scheduler.logger.Error().Err(err).Msg("get database current block number error")
+		cancel()
+		return
+	}
+
+	if currentBlockNumber.Int64() < scheduler.config.Scheduler.StartAt {
+		bi := big.NewInt(scheduler.config.Scheduler.StartAt)
+		currentBlockNumber = model.GormBigInt(*bi)
 
+		err = scheduler.storageSvc.UpdateCurrentBlockNumber(ctx, currentBlockNumber, onlineBockNumber)
+		if err != nil {
+			scheduler.logger.Error().Int64("block_number", currentBlockNumber.Int64()).Err(err).Msg("update db current block number error")
 			cancel()
-		case <-done:
-			return nil
+			return
+		}
+	}
+
+	from := currentBlockNumber.Int64() - int64(scheduler.config.Scheduler.UnstableNumber) // update unstable block
+	stability := model.DepthStability(number, scheduler.config.Scheduler.UnstableNumber)
+	var safe, finalized *big.Int
+	if scheduler.config.Scheduler.Stability == config.StabilityFinality {
+		from, safe, finalized, err = scheduler._Finality(ctx, currentBlockNumber)
+		if err != nil {
+			scheduler.logger.Error().Err(err).Msg("get finality error")
+			cancel()
+			return
 		}
+		stability = model.FinalityStability(safe, finalized)
+	}
+	if from < 0 {
+		from = 0
+	}
+
+	// push the pushed blocks again to update their stability
+	for i := from; i <= currentBlockNumber.Int64() && i <= number.Int64(); i++ {
+		if err := scheduler._Push(big.NewInt(i), stability); err != nil {
+			scheduler.logger.Error().Int64("block_number", i).Err(err).Msg("push crawler id error")
+			cancel()
+			return
+		}
+	}
+	// the finality is recorded after the re-push, so a failed push is pushed again from the previous finalized head
+	if safe != nil && finalized != nil {
+		if err := scheduler.storageSvc.UpdateFinality(ctx, model.GormBigInt(*safe), model.GormBigInt(*finalized)); err != nil {
+			scheduler.logger.Error().Err(err).Msg("update finality error")
+			cancel()
+			return
+		}
+	}
+
+	// then advance the current block number towards the head, it never moves backwards
+	i := currentBlockNumber.Int64() + 1
+	limit := i + scheduler.config.Scheduler.BatchLimit
+	for ; i <= number.Int64() && i < limit; i++ {
+		if err := scheduler._Push(big.NewInt(i), stability); err != nil {
+			scheduler.logger.Error().Int64("block_number", i).Err(err).Msg("push crawler id error")
+			break
+		}
+	}
+	number = big.NewInt(i - 1)
+	err = scheduler.storageSvc.UpdateCurrentBlockNumber(ctx, model.GormBigInt(*number), onlineBockNumber)
+	if err != nil {
+		scheduler.logger.Error().Int64("block_number", i).Err(err).Msg("update db current block number error")
+		cancel()
+		return
+	}
+
+	cancel()
+}
+
+// _Push publishes the block to the crawler with its stability
+func (scheduler *Scheduler) _Push(n *big.Int, stability model.Stability) error {
+	scheduler.logger.Info().Int64("block_number", n.Int64()).Msg("push crawler id")
+	isStable, finality := stability(n)
+	message := model.CrawlerMessage{
+		IsStable:    isStable,
+		BlockNumber: model.GormBigInt(*n),
+		Finality:    finality,
+	}
+	messageBytes, err := model.MarshalCrawlerMessage(model.MessageEncoding(scheduler.config.MQ.MessageEncoding), message)
+	if err != nil {
+		return err
+	}
+	return scheduler.mq.Publish(scheduler.config.Crawler.Topic, model.MessageKey(n), messageBytes)
+}
+
+// _Finality returns the safe and finalized heads of the node and where to push from,
+// the blocks after the recorded finalized head are pushed again to update their finality
+func (scheduler *Scheduler) _Finality(ctx context.Context, currentBlockNumber model.GormBigInt) (from int64, safe *big.Int, finalized *big.Int, err error) {
+	safe, finalized, err = scheduler.crawler.GetFinality(ctx)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	_, previous, err := scheduler.storageSvc.GetFinality(ctx)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	from = currentBlockNumber.Int64()
+	if previous.BigInt().Sign() == 0 { // never recorded
+		from -= int64(scheduler.config.Scheduler.UnstableNumber)
+	} else if previous.Int64()+1 < from {
+		from = previous.Int64() + 1
+	}
+	scheduler.logger.Info().Int64("safe", safe.Int64()).Int64("finalized", finalized.Int64()).Int64("from", from).Msg("get finality")
+	return from, safe, finalized, nil
+}
+
+// Stop stops pushing blocks, the queue, database and node client are left open
+func (scheduler *Scheduler) Stop() {
+	if scheduler.close != nil { // nil before started
+		scheduler.close()
 	}
 }
 
 func (scheduler *Scheduler) Shutdown() error {
-	scheduler.close()
+	scheduler.Stop()
 	scheduler.crawler.Close()
 	if err := scheduler.storageSvc.Close(); err != nil {
 		return err
@@ -0,0 +1,205 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync-ethereum/internal/config"
+	"sync-ethereum/internal/model"
+	"sync-ethereum/internal/service"
+	"sync-ethereum/pkg/mq"
+
+	"github.com/rs/zerolog"
+)
+
+type _Damage string
+
+const (
+	_DamageMissing         _Damage = "missing"
+	_DamageTxCountMismatch _Damage = "tx_count_mismatch"
+	_DamageBrokenLink      _Damage = "broken_parent_link"
+)
+
+func NewVerify(config config.Config, logger zerolog.Logger, mq mq.MQ, crawler service.CrawlerService, storageSvc service.StorageService) *Verify {
+	return &Verify{
+		config:     config,
+		logger:     logger,
+		mq:         mq,
+		crawler:    crawler,
+		storageSvc: storageSvc,
+	}
+}
+
+// Option is the range to verify, To is the current block number of the scheduler when it is nil
+type Option struct {
+	From      *big.Int
+	To        *big.Int
+	BatchSize int64
+	DryRun    bool
+}
+
+// Verify scans the stored blocks for holes, incomplete transactions and broken parent hash links,
+// and pushes the damaged blocks back to the crawler
+type Verify struct {
+	config     config.Config
+	logger     zerolog.Logger
+	mq         mq.MQ
+	crawler    service.CrawlerService
+	storageSvc service.StorageService
+
+	lock   sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (v *Verify) Start(option Option) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	v.lock.Lock()
+	v.cancel = cancel
+	v.done = make(chan struct{})
+	v.lock.Unlock()
+	defer close(v.done)
+	defer cancel()
+
+	if option.To == nil {
+		current, err := v.storageSvc.GetCurrentBlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		option.To = current.BigInt()
+	}
+	if option.From.Sign() < 0 || option.From.Cmp(option.To) > 0 {
+		return fmt.Errorf("invalid range from %s to %s", option.From, option.To)
+	}
+	if option.BatchSize <= 0 {
+		return fmt.Errorf("invalid batch size %d", option.BatchSize)
+	}
+	stability, err := v._Stability(ctx)
+	if err != nil {
+		return err
+	}
+
+	from, to := option.From.Int64(), option.To.Int64()
+	damaged := 0
+	var previous *model.BlockSummary
+	for start := from; start <= to && ctx.Err() == nil; start += option.BatchSize {
+		end := start + option.BatchSize - 1
+		if end > to {
+			end = to
+		}
+		summaries, err := v.storageSvc.ListBlockSummary(ctx, model.BlockRange{From: big.NewInt(start), To: big.NewInt(end)})
+		if err != nil {
+			return err
+		}
+
+		var damages map[int64]_Damage
+		damages, previous, err = v._Check(ctx, start, end, summaries, previous)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return err
+		}
+		for number := start; number <= end; number++ {
+			damage, ok := damages[number]
+			if !ok {
+				continue
+			}
+			damaged++
+			v.logger.Warn().Int64("block_number", number).Str("damage", string(damage)).Msg("damaged block detected")
+			if option.DryRun {
+				continue
+			}
+			if err := v._Republish(big.NewInt(number), stability); err != nil {
+				return err
+			}
+		}
+		v.logger.Info().Int64("from", start).Int64("to", end).Int("damaged", len(damages)).Msg("blocks verified")
+	}
+	if ctx.Err() != nil {
+		v.logger.Warn().Msg("verify interrupted")
+		return nil
+	}
+	v.logger.Info().Int64("from", from).Int64("to", to).Int("damaged", damaged).Bool("dry_run", option.DryRun).Msg("verify complete")
+	return nil
+}
+
+// _Check compares the stored blocks from start to end with the chain, previous is the stored block before start.
+// Only the child of a broken link is pushed back, the crawler detects the stale parent by the reorg detection.
+func (v *Verify) _Check(ctx context.Context, start, end int64, summaries []model.BlockSummary, previous *model.BlockSummary) (map[int64]_Damage, *model.BlockSummary, error) {
+	damages := map[int64]_Damage{}
+	idx := 0
+	for number := start; number <= end; number++ {
+		if idx >= len(summaries) || summaries[idx].BlockNumber.Int64() != number {
+			damages[number] = _DamageMissing
+			previous = nil
+			continue
+		}
+		summary := &summaries[idx]
+		idx++
+
+		if previous != nil && previous.BlockNumber.Int64() == number-1 && previous.BlockHash != summary.ParentHash {
+			damages[number] = _DamageBrokenLink
+		}
+		txCount, err := v.crawler.GetBlockTransactionCount(ctx, big.NewInt(number))
+		if err != nil {
+			return nil, nil, err
+		}
+		if int(txCount) != summary.TxCount {
+			damages[number] = _DamageTxCountMismatch
+		}
+		previous = summary
+	}
+	return damages, previous, nil
+}
+
+// _Stability returns how to mark a block by the head of the node, in the same way as the scheduler
+func (v *Verify) _Stability(ctx context.Context) (model.Stability, error) {
+	if v.config.Scheduler.Stability == config.StabilityFinality {
+		safe, finalized, err := v.crawler.GetFinality(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return model.FinalityStability(safe, finalized), nil
+	}
+
+	head, err := v.crawler.GetBlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return model.DepthStability(head, v.config.Scheduler.UnstableNumber), nil
+}
+
+func (v *Verify) _Republish(number *big.Int, stability model.Stability) error {
+	isStable, finality := stability(number)
+	messageBytes, err := model.MarshalCrawlerMessage(model.MessageEncoding(v.config.MQ.MessageEncoding), model.CrawlerMessage{
+		IsStable:    isStable,
+		BlockNumber: model.GormBigInt(*number),
+		Finality:    finality,
+	})
+	if err != nil {
+		return err
+	}
+	if err := v.mq.Publish(v.config.Crawler.Topic, model.MessageKey(number), messageBytes); err != nil {
+		return err
+	}
+	v.logger.Info().Int64("block_number", number.Int64()).Msg("push damaged block to crawler")
+	return nil
+}
+
+func (v *Verify) Shutdown() error {
+	v.lock.Lock()
+	cancel, done := v.cancel, v.done
+	v.lock.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+
+	v.crawler.Close()
+	if err := v.storageSvc.Close(); err != nil {
+		return err
+	}
+	return v.mq.Close()
+}
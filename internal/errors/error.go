@@ -4,4 +4,5 @@ import "errors"
 
 var (
 	ErrResourceNotFound = errors.New("resource not found")
+	ErrRateLimited      = errors.New("rate limited")
 )
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BackfillStatus string
+
+const (
+	BackfillStatusPending BackfillStatus = "pending"
+	BackfillStatusRunning BackfillStatus = "running"
+	BackfillStatusDone    BackfillStatus = "done"
+)
+
+// BackfillSegment is the progress of publishing a block range to the crawler,
+// NextBlockNumber is the first block which is not published yet
+type BackfillSegment struct {
+	ID              int64          `json:"id" gorm:"primaryKey"`
+	FromBlockNumber GormBigInt     `json:"from_block_num" gorm:"type:varchar(32);column:from_block_num;uniqueIndex:idx_backfill_segment_range"`
+	FromBlockHeight int64          `json:"-" gorm:"index"`
+	ToBlockNumber   GormBigInt     `json:"to_block_num" gorm:"type:varchar(32);column:to_block_num;uniqueIndex:idx_backfill_segment_range"`
+	NextBlockNumber GormBigInt     `json:"next_block_num" gorm:"type:varchar(32);column:next_block_num"`
+	Status          BackfillStatus `json:"status" gorm:"type:varchar(16);index"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+func (segment *BackfillSegment) BeforeCreate(tx *gorm.DB) (err error) {
+	segment.FromBlockHeight = BlockHeight(segment.FromBlockNumber.BigInt())
+	tx.Statement.AddClause(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "from_block_num"}, {Name: "to_block_num"}},
+		DoNothing: true,
+	})
+	return nil
+}
+
+// Sort orders the segments from the lowest block
+func (segment BackfillSegment) Sort(db *gorm.DB) *gorm.DB {
+	return db.Order("from_block_height")
+}
+
+// BackfillSegmentIn filters the stored segments by the exact ranges of the planned ones,
+// the caller keeps the segments in batches below the placeholder limit of the driver
+func BackfillSegmentIn(segments []*BackfillSegment) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(segments) == 0 {
+			return db.Where("1 = 0")
+		}
+		conditions := make([]string, len(segments))
+		values := make([]interface{}, 0, len(segments)*2)
+		for i, segment := range segments {
+			conditions[i] = "(from_block_num = ? AND to_block_num = ?)"
+			values = append(values, segment.FromBlockNumber, segment.ToBlockNumber)
+		}
+		return db.Where("("+strings.Join(conditions, " OR ")+")", values...)
+	}
+}
+
+// PlanBackfillSegments splits [from, to] into segments of size blocks
+func PlanBackfillSegments(from, to *big.Int, size int64) []*BackfillSegment {
+	segments := []*BackfillSegment{}
+	step := big.NewInt(size)
+	for start := new(big.Int).Set(from); start.Cmp(to) <= 0; start = new(big.Int).Add(start, step) {
+		end := new(big.Int).Add(start, big.NewInt(size-1))
+		if end.Cmp(to) > 0 {
+			end = new(big.Int).Set(to)
+		}
+		segments = append(segments, &BackfillSegment{
+			FromBlockNumber: GormBigInt(*start),
+			ToBlockNumber:   GormBigInt(*end),
+			NextBlockNumber: GormBigInt(*start),
+			Status:          BackfillStatusPending,
+		})
+	}
+	return segments
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPlanBackfillSegments(t *testing.T) {
+	tests := []struct {
+		name string
+		from int64
+		to   int64
+		size int64
+		want [][2]int64
+	}{
+		{"even", 0, 9, 5, [][2]int64{{0, 4}, {5, 9}}},
+		{"last segment shorter", 10, 21, 5, [][2]int64{{10, 14}, {15, 19}, {20, 21}}},
+		{"single block", 7, 7, 5, [][2]int64{{7, 7}}},
+		{"segment of one block", 3, 5, 1, [][2]int64{{3, 3}, {4, 4}, {5, 5}}},
+		{"size larger than range", 100, 120, 1000, [][2]int64{{100, 120}}},
+		{"empty range", 10, 9, 5, [][2]int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments := PlanBackfillSegments(big.NewInt(tt.from), big.NewInt(tt.to), tt.size)
+			if len(segments) != len(tt.want) {
+				t.Fatalf("got %d segments, want %d", len(segments), len(tt.want))
+			}
+			for i, segment := range segments {
+				from, to, next := segment.FromBlockNumber.Int64(), segment.ToBlockNumber.Int64(), segment.NextBlockNumber.Int64()
+				if from != tt.want[i][0] || to != tt.want[i][1] {
+					t.Fatalf("segment %d got [%d, %d], want [%d, %d]", i, from, to, tt.want[i][0], tt.want[i][1])
+				}
+				if next != from {
+					t.Fatalf("segment %d got next %d, want %d", i, next, from)
+				}
+				if segment.Status != BackfillStatusPending {
+					t.Fatalf("segment %d got status %s, want %s", i, segment.Status, BackfillStatusPending)
+				}
+			}
+		})
+	}
+}
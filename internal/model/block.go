@@ -9,18 +9,40 @@ import (
 
 type Block struct {
 	BlockNumber GormBigInt     `json:"block_num" gorm:"type:varchar(32);column:block_num;primaryKey;autoIncrement:false"`
+	BlockHeight int64          `json:"-" gorm:"index"`
 	BlockHash   string         `json:"block_hash" gorm:"type:varchar(128);column:block_hash;uniqueIndex:idx_block_parent_hash"`
 	BlockTime   uint64         `json:"block_time"`
 	ParentHash  string         `json:"parent_hash" gorm:"type:varchar(128);column:parent_hash;uniqueIndex:idx_block_parent_hash"`
 	IsStable    bool           `json:"is_stable"`
+	Finality    Finality       `json:"finality" gorm:"type:varchar(16)"`
+	Coinbase    string         `json:"coinbase" gorm:"type:varchar(128)"`
+	GasLimit    uint64         `json:"gas_limit"`
+	GasUsed     uint64         `json:"gas_used"`
+	BaseFee     *GormBigInt    `json:"base_fee" gorm:"type:varchar(32)"`
+	Difficulty  GormBigInt     `json:"difficulty" gorm:"type:varchar(32)"`
+	Nonce       uint64         `json:"nonce"`
+	MixDigest   string         `json:"mix_digest" gorm:"type:varchar(128)"`
+	StateRoot   string         `json:"state_root" gorm:"type:varchar(128)"`
+	TxRoot      string         `json:"tx_root" gorm:"type:varchar(128)"`
+	ReceiptRoot string         `json:"receipt_root" gorm:"type:varchar(128)"`
+	ExtraData   BlockData      `json:"extra_data"`
+	Size        uint64         `json:"size"`
+	UncleCount  int            `json:"uncle_count"`
 	Transaction []*Transaction `gorm:"foreignKey:BlockNumber;references:BlockNumber"`
+	Uncles      []*Uncle       `json:"uncles" gorm:"foreignKey:BlockNumber;references:BlockNumber"`
+	Withdrawals []*Withdrawal  `json:"withdrawals" gorm:"foreignKey:BlockNumber;references:BlockNumber"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   *time.Time     `json:"deleted_at" gorm:"index"`
 }
 
 func (block Block) Preload(db *gorm.DB) *gorm.DB {
-	return db.Preload("Transaction")
+	return db.Preload("Transaction").Preload("Uncles", Uncle{}.Sort)
+}
+
+func (block *Block) BeforeCreate(tx *gorm.DB) (err error) {
+	block.BlockHeight = BlockHeight(block.BlockNumber.BigInt())
+	return nil
 }
 
 func (block Block) OnConflict(db *gorm.DB) *gorm.DB {
@@ -28,3 +50,11 @@ func (block Block) OnConflict(db *gorm.DB) *gorm.DB {
 		UpdateAll: true,
 	})
 }
+
+// BlockSummary is a stored block with the count of its stored transactions, it is used to find incomplete blocks
+type BlockSummary struct {
+	BlockNumber GormBigInt `gorm:"column:block_num"`
+	BlockHash   string
+	ParentHash  string
+	TxCount     int
+}
@@ -5,13 +5,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 type GormBigInt big.Int
@@ -80,6 +83,10 @@ func (bi *GormBigInt) UnmarshalJSON(data []byte) error {
 type BlockData []byte
 
 func (d *BlockData) Scan(value interface{}) error {
+	if value == nil {
+		*d = nil
+		return nil
+	}
 	b, ok := value.([]byte)
 	if !ok {
 		return errors.New(fmt.Sprint("failed convert value to []byte", value))
@@ -93,7 +100,7 @@ func (d BlockData) Value() (driver.Value, error) {
 }
 
 func (d BlockData) MarshalJSON() ([]byte, error) {
-	return []byte(strconv.Quote(common.BytesToHash(d).Hex())), nil
+	return []byte(strconv.Quote(hexutil.Encode(d))), nil
 }
 
 func (d *BlockData) UnmarshalJSON(data []byte) error {
@@ -105,6 +112,55 @@ func (d *BlockData) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// JSON stores raw json in a text column
+type JSON []byte
+
+func (j *JSON) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*j = nil
+	case []byte:
+		*j = append(JSON{}, v...)
+	case string:
+		*j = JSON(v)
+	default:
+		return errors.New(fmt.Sprint("failed convert value to []byte", value))
+	}
+	return nil
+}
+
+func (j JSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	return string(j), nil
+}
+
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+func (j *JSON) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*j = nil
+		return nil
+	}
+	*j = append(JSON{}, data...)
+	return nil
+}
+
+func (JSON) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "mysql":
+		return "longtext"
+	default:
+		return "text"
+	}
+}
+
 type Pagination struct {
 	Page    int64 `json:"page,omitempty"`
 	PerPage int64 `json:"per_page,omitempty"`
@@ -156,3 +212,48 @@ func (s Sorting) Sort(db *gorm.DB) *gorm.DB {
 
 	return db
 }
+
+// BlockHeight is the block number as an integer, the block_num column is stored as decimal string
+// so the tables ranged and ordered by block keep it in the indexed block_height column
+func BlockHeight(blockNumber *big.Int) int64 {
+	if blockNumber == nil {
+		return 0
+	}
+	if !blockNumber.IsInt64() {
+		if blockNumber.Sign() < 0 {
+			return math.MinInt64
+		}
+		return math.MaxInt64
+	}
+	return blockNumber.Int64()
+}
+
+// BlockRange filters the block_height column by number. Both bounds are optional.
+type BlockRange struct {
+	From *big.Int `json:"from_block,omitempty"`
+	To   *big.Int `json:"to_block,omitempty"`
+}
+
+func (r BlockRange) Where(db *gorm.DB) *gorm.DB {
+	if r.From != nil {
+		db = db.Where("block_height >= ?", BlockHeight(r.From))
+	}
+	if r.To != nil {
+		db = db.Where("block_height <= ?", BlockHeight(r.To))
+	}
+	return db
+}
+
+// BlockNumber filters the block_num column, unlike a struct filter it also matches the genesis block
+func BlockNumber(blockNumber GormBigInt) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("block_num = ?", blockNumber)
+	}
+}
+
+// BlockNumberSorting orders by the block_height column
+func BlockNumberSorting(order SortOrder) Sorting {
+	return Sorting{
+		{Field: "block_height", Order: order},
+	}
+}
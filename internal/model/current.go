@@ -4,4 +4,7 @@ type CurrentBlockNumber struct {
 	ID                int64      `json:"id" gorm:"primaryKey"`
 	BlockNumber       GormBigInt `json:"block_num" gorm:"type:varchar(32);column:block_num"`
 	OnlineBlockNumber GormBigInt `json:"online_block_num" gorm:"type:varchar(32);column:online_block_num"`
+	// heads of the safe and finalized tags, recorded in finality stability
+	SafeBlockNumber      GormBigInt `json:"safe_block_num" gorm:"type:varchar(32);column:safe_block_num"`
+	FinalizedBlockNumber GormBigInt `json:"finalized_block_num" gorm:"type:varchar(32);column:finalized_block_num"`
 }
@@ -0,0 +1,416 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync-ethereum/internal/model/pb"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// EnvelopeVersion is the newest version of pb.Envelope this build reads
+	EnvelopeVersion = 2
+	// a message is written with the oldest version carrying its payload, so the older consumers still read it
+	_EnvelopeVersionMessage       = 1
+	_EnvelopeVersionBlobReference = 2
+)
+
+var ErrUnsupportedEnvelopeVersion = errors.New("unsupported envelope version")
+
+// MessageEncoding is how the producers encode the messages of the crawler and database writer topics,
+// the consumers read both, so the producers switch to protobuf after all the consumers are upgraded
+type MessageEncoding string
+
+const (
+	MessageEncodingJSON     MessageEncoding = "json"
+	MessageEncodingProtobuf MessageEncoding = "protobuf"
+)
+
+func MarshalCrawlerMessage(encoding MessageEncoding, message CrawlerMessage) ([]byte, error) {
+	switch encoding {
+	case MessageEncodingJSON, "":
+		return json.Marshal(message)
+	case MessageEncodingProtobuf:
+		return proto.Marshal(&pb.Envelope{
+			Version: _EnvelopeVersionMessage,
+			Payload: &pb.Envelope_CrawlerMessage{CrawlerMessage: &pb.CrawlerMessage{
+				IsStable:    message.IsStable,
+				BlockNumber: message.BlockNumber.BigInt().String(),
+				Finality:    string(message.Finality),
+			}},
+		})
+	default:
+		return nil, fmt.Errorf("no supported message encoding [%s]", encoding)
+	}
+}
+
+func UnmarshalCrawlerMessage(data []byte) (CrawlerMessage, error) {
+	message := CrawlerMessage{}
+	if _IsJSON(data) {
+		err := json.Unmarshal(data, &message)
+		return message, err
+	}
+
+	envelope, err := _UnmarshalEnvelope(data)
+	if err != nil {
+		return message, err
+	}
+	payload := envelope.GetCrawlerMessage()
+	if payload == nil {
+		return message, errors.New("envelope is not a crawler message")
+	}
+	message.IsStable = payload.IsStable
+	message.Finality = Finality(payload.Finality)
+	message.BlockNumber, err = _ParseGormBigInt(payload.BlockNumber)
+	return message, err
+}
+
+func MarshalBlock(encoding MessageEncoding, block Block) ([]byte, error) {
+	switch encoding {
+	case MessageEncodingJSON, "":
+		return json.Marshal(block)
+	case MessageEncodingProtobuf:
+		return proto.Marshal(&pb.Envelope{
+			Version: _EnvelopeVersionMessage,
+			Payload: &pb.Envelope_Block{Block: _BlockToPb(block)},
+		})
+	default:
+		return nil, fmt.Errorf("no supported message encoding [%s]", encoding)
+	}
+}
+
+// UnmarshalBlock returns the reference instead when the block is offloaded to the blob store
+func UnmarshalBlock(data []byte) (Block, *BlobReference, error) {
+	block := Block{}
+	if _IsJSON(data) {
+		err := json.Unmarshal(data, &block)
+		return block, nil, err
+	}
+
+	envelope, err := _UnmarshalEnvelope(data)
+	if err != nil {
+		return block, nil, err
+	}
+	if reference := envelope.GetBlockReference(); reference != nil {
+		blobReference := &BlobReference{Key: reference.Key, Size: reference.Size}
+		if reference.TxCount != nil {
+			txCount := int(*reference.TxCount)
+			blobReference.TxCount = &txCount
+		}
+		return block, blobReference, nil
+	}
+	payload := envelope.GetBlock()
+	if payload == nil {
+		return block, nil, errors.New("envelope is not a block")
+	}
+	block, err = _BlockFromPb(payload)
+	return block, nil, err
+}
+
+// BlobReference points to an encoded message offloaded to the blob store because of its size
+type BlobReference struct {
+	Key  string
+	Size uint64
+	// TxCount of the referenced block, nil for a reference written before it was added
+	TxCount *int
+}
+
+// BlobKey is the same for every crawl of a block, so a retried crawl overwrites its blob instead of leaving another one
+func BlobKey(blockNumber *big.Int, blockHash string) string {
+	return "blocks/" + blockNumber.String() + "/" + blockHash
+}
+
+// ParseBlobKey returns the block of a key written by BlobKey
+func ParseBlobKey(key string) (blockNumber *big.Int, blockHash string, ok bool) {
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 || parts[0] != "blocks" || len(parts[2]) == 0 {
+		return nil, "", false
+	}
+	blockNumber, ok = new(big.Int).SetString(parts[1], 10)
+	if !ok {
+		return nil, "", false
+	}
+	return blockNumber, parts[2], true
+}
+
+// MarshalBlockReference is always protobuf, a reference has no json encoding
+func MarshalBlockReference(reference BlobReference) ([]byte, error) {
+	message := &pb.BlobReference{
+		Key:  reference.Key,
+		Size: reference.Size,
+	}
+	if reference.TxCount != nil {
+		txCount := uint32(*reference.TxCount)
+		message.TxCount = &txCount
+	}
+	return proto.Marshal(&pb.Envelope{
+		Version: _EnvelopeVersionBlobReference,
+		Payload: &pb.Envelope_BlockReference{BlockReference: message},
+	})
+}
+
+// EnvelopeVersionOf reads the version of an encoded message without decoding its payload,
+// it is 0 for the json written before the envelope
+func EnvelopeVersionOf(data []byte) uint32 {
+	if _IsJSON(data) {
+		return 0
+	}
+	number, typ, n := protowire.ConsumeTag(data)
+	if n < 0 || number != 1 || typ != protowire.VarintType { // the version is the first field
+		return 0
+	}
+	version, m := protowire.ConsumeVarint(data[n:])
+	if m < 0 {
+		return 0
+	}
+	return uint32(version)
+}
+
+// _IsJSON tells the messages written before the envelope, an encoded envelope starts with the tag of its version
+func _IsJSON(data []byte) bool {
+	return len(data) > 0 && data[0] == '{'
+}
+
+func _UnmarshalEnvelope(data []byte) (*pb.Envelope, error) {
+	envelope := &pb.Envelope{}
+	if err := proto.Unmarshal(data, envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Version == 0 || envelope.Version > EnvelopeVersion {
+		return nil, fmt.Errorf("%w %d, the newest supported is %d", ErrUnsupportedEnvelopeVersion, envelope.Version, EnvelopeVersion)
+	}
+	return envelope, nil
+}
+
+func _ParseGormBigInt(s string) (GormBigInt, error) {
+	if len(s) == 0 {
+		return GormBigInt{}, nil
+	}
+	bi, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return GormBigInt{}, fmt.Errorf("bigint can't convert %s to *big.Int", s)
+	}
+	return GormBigInt(*bi), nil
+}
+
+func _ParseGormBigIntPtr(s *string) (*GormBigInt, error) {
+	if s == nil {
+		return nil, nil
+	}
+	bi, err := _ParseGormBigInt(*s)
+	return &bi, err
+}
+
+func _GormBigIntString(bi *GormBigInt) *string {
+	if bi == nil {
+		return nil
+	}
+	s := bi.BigInt().String()
+	return &s
+}
+
+func _BlockToPb(block Block) *pb.Block {
+	message := &pb.Block{
+		BlockNumber: block.BlockNumber.BigInt().String(),
+		BlockHash:   block.BlockHash,
+		BlockTime:   block.BlockTime,
+		ParentHash:  block.ParentHash,
+		IsStable:    block.IsStable,
+		Finality:    string(block.Finality),
+		Coinbase:    block.Coinbase,
+		GasLimit:    block.GasLimit,
+		GasUsed:     block.GasUsed,
+		BaseFee:     _GormBigIntString(block.BaseFee),
+		Difficulty:  block.Difficulty.BigInt().String(),
+		Nonce:       block.Nonce,
+		MixDigest:   block.MixDigest,
+		StateRoot:   block.StateRoot,
+		TxRoot:      block.TxRoot,
+		ReceiptRoot: block.ReceiptRoot,
+		ExtraData:   block.ExtraData,
+		Size:        block.Size,
+		UncleCount:  int64(block.UncleCount),
+	}
+	for _, tx := range block.Transaction {
+		pbTx := &pb.Transaction{
+			TxHash:            tx.TXHash,
+			BlockNumber:       tx.BlockNumber.BigInt().String(),
+			TxIndex:           uint32(tx.TxIndex),
+			From:              tx.From,
+			To:                tx.To,
+			Nonce:             tx.Nonce,
+			Data:              tx.Data,
+			Value:             tx.Value.BigInt().String(),
+			Type:              uint32(tx.Type),
+			Gas:               tx.Gas,
+			GasPrice:          tx.GasPrice.BigInt().String(),
+			GasTipCap:         _GormBigIntString(tx.GasTipCap),
+			GasFeeCap:         _GormBigIntString(tx.GasFeeCap),
+			AccessList:        tx.AccessList,
+			BlobGasFeeCap:     _GormBigIntString(tx.BlobGasFeeCap),
+			BlobHashes:        tx.BlobHashes,
+			Status:            tx.Status,
+			GasUsed:           tx.GasUsed,
+			CumulativeGasUsed: tx.CumulativeGasUsed,
+			EffectiveGasPrice: tx.EffectiveGasPrice.BigInt().String(),
+			ContractAddress:   tx.ContractAddress,
+		}
+		for _, log := range tx.Logs {
+			pbTx.Logs = append(pbTx.Logs, &pb.TransactionLog{
+				TxHash:      log.TXHash,
+				TxIndex:     uint32(log.TxIndex),
+				BlockNumber: log.BlockNumber.BigInt().String(),
+				Address:     log.Address,
+				Topics:      log.Topics(),
+				Index:       log.Index,
+				Data:        log.Data,
+				Removed:     log.Removed,
+			})
+		}
+		message.Transactions = append(message.Transactions, pbTx)
+	}
+	for _, uncle := range block.Uncles {
+		message.Uncles = append(message.Uncles, &pb.Uncle{
+			BlockNumber: uncle.BlockNumber.BigInt().String(),
+			Position:    int64(uncle.Position),
+			UncleHash:   uncle.UncleHash,
+			UncleNumber: uncle.UncleNumber.BigInt().String(),
+			ParentHash:  uncle.ParentHash,
+			Coinbase:    uncle.Coinbase,
+			Difficulty:  uncle.Difficulty.BigInt().String(),
+			GasLimit:    uncle.GasLimit,
+			GasUsed:     uncle.GasUsed,
+			UncleTime:   uncle.UncleTime,
+			ExtraData:   uncle.ExtraData,
+		})
+	}
+	for _, withdrawal := range block.Withdrawals {
+		message.Withdrawals = append(message.Withdrawals, &pb.Withdrawal{
+			BlockNumber:    withdrawal.BlockNumber.BigInt().String(),
+			Index:          withdrawal.Index,
+			ValidatorIndex: withdrawal.ValidatorIndex,
+			Address:        withdrawal.Address,
+			Amount:         withdrawal.Amount,
+		})
+	}
+	return message
+}
+
+func _BlockFromPb(message *pb.Block) (block Block, err error) {
+	// the first parse error is kept
+	parse := func(s string) GormBigInt {
+		bi, parseErr := _ParseGormBigInt(s)
+		if err == nil {
+			err = parseErr
+		}
+		return bi
+	}
+	parsePtr := func(s *string) *GormBigInt {
+		bi, parseErr := _ParseGormBigIntPtr(s)
+		if err == nil {
+			err = parseErr
+		}
+		return bi
+	}
+
+	block = Block{
+		BlockNumber: parse(message.BlockNumber),
+		BlockHash:   message.BlockHash,
+		BlockTime:   message.BlockTime,
+		ParentHash:  message.ParentHash,
+		IsStable:    message.IsStable,
+		Finality:    Finality(message.Finality),
+		Coinbase:    message.Coinbase,
+		GasLimit:    message.GasLimit,
+		GasUsed:     message.GasUsed,
+		BaseFee:     parsePtr(message.BaseFee),
+		Difficulty:  parse(message.Difficulty),
+		Nonce:       message.Nonce,
+		MixDigest:   message.MixDigest,
+		StateRoot:   message.StateRoot,
+		TxRoot:      message.TxRoot,
+		ReceiptRoot: message.ReceiptRoot,
+		ExtraData:   message.ExtraData,
+		Size:        message.Size,
+		UncleCount:  int(message.UncleCount),
+	}
+	if len(message.Transactions) > 0 {
+		block.Transaction = make([]*Transaction, len(message.Transactions))
+	}
+	for i, pbTx := range message.Transactions {
+		tx := &Transaction{
+			TXHash:            pbTx.TxHash,
+			BlockNumber:       parse(pbTx.BlockNumber),
+			TxIndex:           uint(pbTx.TxIndex),
+			From:              pbTx.From,
+			To:                pbTx.To,
+			Nonce:             pbTx.Nonce,
+			Data:              pbTx.Data,
+			Value:             parse(pbTx.Value),
+			Type:              uint8(pbTx.Type),
+			Gas:               pbTx.Gas,
+			GasPrice:          parse(pbTx.GasPrice),
+			GasTipCap:         parsePtr(pbTx.GasTipCap),
+			GasFeeCap:         parsePtr(pbTx.GasFeeCap),
+			AccessList:        pbTx.AccessList,
+			BlobGasFeeCap:     parsePtr(pbTx.BlobGasFeeCap),
+			BlobHashes:        pbTx.BlobHashes,
+			Status:            pbTx.Status,
+			GasUsed:           pbTx.GasUsed,
+			CumulativeGasUsed: pbTx.CumulativeGasUsed,
+			EffectiveGasPrice: parse(pbTx.EffectiveGasPrice),
+			ContractAddress:   pbTx.ContractAddress,
+			Logs:              make([]*TransactionLog, len(pbTx.Logs)),
+		}
+		for j, pbLog := range pbTx.Logs {
+			log := &TransactionLog{
+				TXHash:      pbLog.TxHash,
+				TxIndex:     uint(pbLog.TxIndex),
+				BlockNumber: parse(pbLog.BlockNumber),
+				Address:     pbLog.Address,
+				Index:       pbLog.Index,
+				Data:        pbLog.Data,
+				Removed:     pbLog.Removed,
+			}
+			columns := []*string{&log.Topic0, &log.Topic1, &log.Topic2, &log.Topic3}
+			for k, topic := range pbLog.Topics {
+				if k >= len(columns) {
+					break
+				}
+				*columns[k] = topic
+			}
+			tx.Logs[j] = log
+		}
+		block.Transaction[i] = tx
+	}
+	for _, pbUncle := range message.Uncles {
+		block.Uncles = append(block.Uncles, &Uncle{
+			BlockNumber: parse(pbUncle.BlockNumber),
+			Position:    int(pbUncle.Position),
+			UncleHash:   pbUncle.UncleHash,
+			UncleNumber: parse(pbUncle.UncleNumber),
+			ParentHash:  pbUncle.ParentHash,
+			Coinbase:    pbUncle.Coinbase,
+			Difficulty:  parse(pbUncle.Difficulty),
+			GasLimit:    pbUncle.GasLimit,
+			GasUsed:     pbUncle.GasUsed,
+			UncleTime:   pbUncle.UncleTime,
+			ExtraData:   pbUncle.ExtraData,
+		})
+	}
+	for _, pbWithdrawal := range message.Withdrawals {
+		block.Withdrawals = append(block.Withdrawals, &Withdrawal{
+			BlockNumber:    parse(pbWithdrawal.BlockNumber),
+			Index:          pbWithdrawal.Index,
+			ValidatorIndex: pbWithdrawal.ValidatorIndex,
+			Address:        pbWithdrawal.Address,
+			Amount:         pbWithdrawal.Amount,
+		})
+	}
+	return block, err
+}
@@ -0,0 +1,376 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync-ethereum/internal/model/pb"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/protobuf/proto"
+)
+
+func _GormBigInt(s string) GormBigInt {
+	bi, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic(s)
+	}
+	return GormBigInt(*bi)
+}
+
+func _GormBigIntPtr(s string) *GormBigInt {
+	bi := _GormBigInt(s)
+	return &bi
+}
+
+func _TestBlock() Block {
+	log := &TransactionLog{
+		TXHash:      "0xtx",
+		TxIndex:     1,
+		BlockNumber: _GormBigInt("100"),
+		Address:     "0xcontract",
+		Index:       3,
+		Data:        BlockData{0x01, 0x02},
+	}
+	log.SetTopics([]common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02")})
+	return Block{
+		BlockNumber: _GormBigInt("100"),
+		BlockHash:   "0xblock",
+		BlockTime:   1700000000,
+		ParentHash:  "0xparent",
+		IsStable:    true,
+		Finality:    FinalityFinalized,
+		Coinbase:    "0xcoinbase",
+		GasLimit:    30000000,
+		GasUsed:     21000,
+		BaseFee:     _GormBigIntPtr("7"),
+		Difficulty:  _GormBigInt("0"),
+		Nonce:       42,
+		MixDigest:   "0xmix",
+		StateRoot:   "0xstate",
+		TxRoot:      "0xtxroot",
+		ReceiptRoot: "0xreceipt",
+		ExtraData:   BlockData("extra"),
+		Size:        1024,
+		UncleCount:  1,
+		Transaction: []*Transaction{
+			{
+				TXHash:            "0xtx",
+				BlockNumber:       _GormBigInt("100"),
+				TxIndex:           1,
+				From:              "0xfrom",
+				To:                "0xto",
+				Nonce:             5,
+				Data:              BlockData{0xff},
+				Value:             _GormBigInt("1000000000000000000"),
+				Type:              2,
+				Gas:               21000,
+				GasPrice:          _GormBigInt("9"),
+				GasTipCap:         _GormBigIntPtr("2"),
+				GasFeeCap:         _GormBigIntPtr("10"),
+				AccessList:        JSON(`[{"address":"0xto","storageKeys":[]}]`),
+				Status:            1,
+				GasUsed:           21000,
+				CumulativeGasUsed: 21000,
+				EffectiveGasPrice: _GormBigInt("9"),
+				Logs:              []*TransactionLog{log},
+			},
+			{
+				TXHash:            "0xlegacy",
+				BlockNumber:       _GormBigInt("100"),
+				TxIndex:           2,
+				From:              "0xfrom",
+				Data:              BlockData{0x00},
+				Value:             _GormBigInt("0"),
+				GasPrice:          _GormBigInt("9"),
+				EffectiveGasPrice: _GormBigInt("9"),
+				ContractAddress:   "0xcreated",
+				Logs:              []*TransactionLog{},
+			},
+		},
+		Uncles: []*Uncle{{
+			BlockNumber: _GormBigInt("100"),
+			Position:    0,
+			UncleHash:   "0xuncle",
+			UncleNumber: _GormBigInt("99"),
+			ParentHash:  "0xuncleparent",
+			Coinbase:    "0xminer",
+			Difficulty:  _GormBigInt("131072"),
+			GasLimit:    30000000,
+			UncleTime:   1699999990,
+			ExtraData:   BlockData("uncle"),
+		}},
+		Withdrawals: []*Withdrawal{{
+			BlockNumber:    _GormBigInt("100"),
+			Index:          7,
+			ValidatorIndex: 8,
+			Address:        "0xvalidator",
+			Amount:         32000000000,
+		}},
+	}
+}
+
+func _MarshalEnvelope(t *testing.T, envelope *pb.Envelope) []byte {
+	t.Helper()
+	data, err := proto.Marshal(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestCrawlerMessageEnvelope(t *testing.T) {
+	tests := []struct {
+		name      string
+		encodings []MessageEncoding
+		message   CrawlerMessage
+	}{
+		{"depth", []MessageEncoding{MessageEncodingJSON, MessageEncodingProtobuf}, CrawlerMessage{IsStable: true, BlockNumber: _GormBigInt("100")}},
+		{"finality", []MessageEncoding{MessageEncodingJSON, MessageEncodingProtobuf}, CrawlerMessage{BlockNumber: _GormBigInt("100"), Finality: FinalitySafe}},
+		{"genesis", []MessageEncoding{MessageEncodingJSON, MessageEncodingProtobuf}, CrawlerMessage{IsStable: true, BlockNumber: _GormBigInt("0")}},
+		// json writes a block number as int64, only protobuf carries a larger one
+		{"larger than int64", []MessageEncoding{MessageEncodingProtobuf}, CrawlerMessage{BlockNumber: _GormBigInt("18446744073709551621")}},
+	}
+	for _, tt := range tests {
+		for _, encoding := range tt.encodings {
+			t.Run(tt.name+" "+string(encoding), func(t *testing.T) {
+				data, err := MarshalCrawlerMessage(encoding, tt.message)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := UnmarshalCrawlerMessage(data)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.IsStable != tt.message.IsStable || got.Finality != tt.message.Finality || got.BlockNumber.BigInt().Cmp(tt.message.BlockNumber.BigInt()) != 0 {
+					t.Fatalf("got %+v, want %+v", got, tt.message)
+				}
+			})
+		}
+	}
+}
+
+func TestUnmarshalCrawlerMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    CrawlerMessage
+		wantErr error
+	}{
+		{
+			name: "legacy json",
+			data: []byte(`{"is_stable":true,"block_number":12345}`),
+			want: CrawlerMessage{IsStable: true, BlockNumber: _GormBigInt("12345")},
+		},
+		{
+			name: "unknown field",
+			data: []byte(`{"is_stable":false,"block_number":1,"finality":"latest","trace":"x"}`),
+			want: CrawlerMessage{BlockNumber: _GormBigInt("1"), Finality: FinalityLatest},
+		},
+		{
+			name: "newer version",
+			data: _MarshalEnvelope(t, &pb.Envelope{Version: EnvelopeVersion + 1, Payload: &pb.Envelope_CrawlerMessage{
+				CrawlerMessage: &pb.CrawlerMessage{BlockNumber: "1"},
+			}}),
+			wantErr: ErrUnsupportedEnvelopeVersion,
+		},
+		{
+			name: "no version",
+			data: _MarshalEnvelope(t, &pb.Envelope{Payload: &pb.Envelope_CrawlerMessage{
+				CrawlerMessage: &pb.CrawlerMessage{BlockNumber: "1"},
+			}}),
+			wantErr: ErrUnsupportedEnvelopeVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalCrawlerMessage(tt.data)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.IsStable != tt.want.IsStable || got.Finality != tt.want.Finality || got.BlockNumber.BigInt().Cmp(tt.want.BlockNumber.BigInt()) != 0 {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	block, err := MarshalBlock(MessageEncodingProtobuf, _TestBlock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UnmarshalCrawlerMessage(block); err == nil {
+		t.Fatal("got no error for a block envelope")
+	}
+}
+
+func TestBlockEnvelope(t *testing.T) {
+	noFees := _TestBlock()
+	noFees.BaseFee = nil
+	for _, tx := range noFees.Transaction {
+		tx.GasTipCap, tx.GasFeeCap = nil, nil
+	}
+	empty := Block{BlockNumber: _GormBigInt("1"), BlockHash: "0xempty", Difficulty: _GormBigInt("0")}
+
+	tests := []struct {
+		name  string
+		block Block
+	}{
+		{"full block", _TestBlock()},
+		{"no fees", noFees},
+		{"empty block", empty},
+	}
+	for _, tt := range tests {
+		for _, encoding := range []MessageEncoding{MessageEncodingJSON, MessageEncodingProtobuf} {
+			t.Run(tt.name+" "+string(encoding), func(t *testing.T) {
+				data, err := MarshalBlock(encoding, tt.block)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, reference, err := UnmarshalBlock(data)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if reference != nil {
+					t.Fatalf("got reference %+v, want the block", reference)
+				}
+				_AssertSameJSON(t, got, tt.block)
+			})
+		}
+	}
+}
+
+func TestUnmarshalBlock(t *testing.T) {
+	legacy := []byte(`{
+		"block_num": 100, "block_hash": "0xblock", "block_time": 1700000000, "parent_hash": "0xparent",
+		"is_stable": true, "coinbase": "0xcoinbase", "gas_limit": 30000000, "gas_used": 21000,
+		"base_fee": 7, "difficulty": 0, "nonce": 42, "extra_data": "0x6578747261", "size": 1024,
+		"Transaction": [{
+			"tx_hash": "0xtx", "block_num": 100, "tx_index": 1, "from": "0xfrom", "to": "0xto",
+			"value": 1, "gas_price": 9, "gas_tip_cap": null, "effective_gas_price": 9, "status": 1,
+			"logs": [{"tx_hash": "0xtx", "block_num": 100, "address": "0xcontract", "topic0": "0x01", "index": 3}]
+		}],
+		"uncles": null, "withdrawals": null
+	}`)
+	got, reference, err := UnmarshalBlock(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reference != nil {
+		t.Fatalf("got reference %+v, want the block", reference)
+	}
+	if got.BlockNumber.Int64() != 100 || got.BlockHash != "0xblock" || got.BaseFee == nil || got.BaseFee.Int64() != 7 || string(got.ExtraData) != "extra" {
+		t.Fatalf("got block %+v", got)
+	}
+	if len(got.Transaction) != 1 || got.Transaction[0].GasTipCap != nil || len(got.Transaction[0].Logs) != 1 {
+		t.Fatalf("got transactions %+v", got.Transaction)
+	}
+	if topics := got.Transaction[0].Logs[0].Topics(); len(topics) != 1 || topics[0] != "0x01" {
+		t.Fatalf("got topics %v", topics)
+	}
+
+	txCount := 0
+	for _, want := range []BlobReference{
+		{Key: "blocks/100/0xblock", Size: 4096},
+		{Key: "blocks/100/0xblock", Size: 4096, TxCount: &txCount},
+	} {
+		data, err := MarshalBlockReference(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, reference, err := UnmarshalBlock(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reference == nil || reference.Key != want.Key || reference.Size != want.Size {
+			t.Fatalf("got reference %+v", reference)
+		}
+		if (reference.TxCount == nil) != (want.TxCount == nil) || (reference.TxCount != nil && *reference.TxCount != *want.TxCount) {
+			t.Fatalf("got tx count %v, want %v", reference.TxCount, want.TxCount)
+		}
+	}
+
+	for _, version := range []uint32{0, EnvelopeVersion + 1} {
+		data := _MarshalEnvelope(t, &pb.Envelope{Version: version, Payload: &pb.Envelope_Block{Block: _BlockToPb(_TestBlock())}})
+		if _, _, err := UnmarshalBlock(data); !errors.Is(err, ErrUnsupportedEnvelopeVersion) {
+			t.Fatalf("version %d got error %v, want %v", version, err, ErrUnsupportedEnvelopeVersion)
+		}
+	}
+
+	message, err := MarshalCrawlerMessage(MessageEncodingProtobuf, CrawlerMessage{BlockNumber: _GormBigInt("1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := UnmarshalBlock(message); err == nil {
+		t.Fatal("got no error for a crawler message envelope")
+	}
+}
+
+// _AssertSameJSON compares the blocks by their json, which leaves out the columns filled by the database
+func _AssertSameJSON(t *testing.T, got, want Block) {
+	t.Helper()
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(gotJSON) != string(wantJSON) {
+		t.Fatalf("got %s\nwant %s", gotJSON, wantJSON)
+	}
+}
+
+func TestBlobKey(t *testing.T) {
+	key := BlobKey(_GormBigInt("18446744073709551621").BigInt(), "0xabc")
+	if key != "blocks/18446744073709551621/0xabc" {
+		t.Fatalf("got key %s", key)
+	}
+	number, hash, ok := ParseBlobKey(key)
+	if !ok || number.String() != "18446744073709551621" || hash != "0xabc" {
+		t.Fatalf("got %v %s %t", number, hash, ok)
+	}
+
+	for _, key := range []string{"", "blocks/100", "blocks/100/", "blocks/x/0xabc", "other/100/0xabc", "blocks/100/0xabc/more"} {
+		if _, _, ok := ParseBlobKey(key); ok {
+			t.Fatalf("got key %s parsed", key)
+		}
+	}
+}
+
+func TestEnvelopeVersionOf(t *testing.T) {
+	message, err := MarshalCrawlerMessage(MessageEncodingProtobuf, CrawlerMessage{BlockNumber: _GormBigInt("1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reference, err := MarshalBlockReference(BlobReference{Key: "blocks/1/0xabc", Size: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		data []byte
+		want uint32
+	}{
+		{"json", []byte(`{"is_stable":true,"block_number":1}`), 0},
+		{"message", message, _EnvelopeVersionMessage},
+		{"reference", reference, _EnvelopeVersionBlobReference},
+		{"newer version", _MarshalEnvelope(t, &pb.Envelope{Version: EnvelopeVersion + 1}), EnvelopeVersion + 1},
+		{"empty", nil, 0},
+		{"truncated", []byte{0x08}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EnvelopeVersionOf(tt.data); got != tt.want {
+				t.Fatalf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
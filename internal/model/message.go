@@ -1,6 +1,57 @@
 package model
 
+import "math/big"
+
 type CrawlerMessage struct {
 	IsStable    bool       `json:"is_stable"`
 	BlockNumber GormBigInt `json:"block_number"`
+	Finality    Finality   `json:"finality,omitempty"`
+}
+
+// MessageKey is the key of the messages of a block, so the partitioned queues keep the messages of a block in order
+func MessageKey(blockNumber *big.Int) string {
+	return blockNumber.String()
+}
+
+// Finality is the block tag of the node which covers the block, it is empty when the stability comes from the depth
+type Finality string
+
+const (
+	FinalityLatest    Finality = "latest"
+	FinalitySafe      Finality = "safe"
+	FinalityFinalized Finality = "finalized"
+)
+
+// NewFinality compares the block number with the safe and finalized heads
+func NewFinality(blockNumber, safe, finalized *big.Int) Finality {
+	if finalized != nil && blockNumber.Cmp(finalized) <= 0 {
+		return FinalityFinalized
+	}
+	if safe != nil && blockNumber.Cmp(safe) <= 0 {
+		return FinalitySafe
+	}
+	return FinalityLatest
+}
+
+func (finality Finality) IsStable() bool {
+	return finality == FinalityFinalized
+}
+
+// Stability marks the block as stable or not, along with its finality
+type Stability func(blockNumber *big.Int) (bool, Finality)
+
+// DepthStability marks the blocks which are at least unstableNumber blocks behind the head as stable
+func DepthStability(head *big.Int, unstableNumber int) Stability {
+	stableHead := new(big.Int).Sub(head, big.NewInt(int64(unstableNumber)))
+	return func(blockNumber *big.Int) (bool, Finality) {
+		return blockNumber.Cmp(stableHead) <= 0, ""
+	}
+}
+
+// FinalityStability marks the finalized blocks as stable
+func FinalityStability(safe, finalized *big.Int) Stability {
+	return func(blockNumber *big.Int) (bool, Finality) {
+		finality := NewFinality(blockNumber, safe, finalized)
+		return finality.IsStable(), finality
+	}
 }
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestNewFinality(t *testing.T) {
+	tests := []struct {
+		name        string
+		blockNumber int64
+		safe        *big.Int
+		finalized   *big.Int
+		want        Finality
+		wantStable  bool
+	}{
+		{"below finalized", 90, big.NewInt(110), big.NewInt(100), FinalityFinalized, true},
+		{"at finalized", 100, big.NewInt(110), big.NewInt(100), FinalityFinalized, true},
+		{"between finalized and safe", 105, big.NewInt(110), big.NewInt(100), FinalitySafe, false},
+		{"at safe", 110, big.NewInt(110), big.NewInt(100), FinalitySafe, false},
+		{"after safe", 111, big.NewInt(110), big.NewInt(100), FinalityLatest, false},
+		{"no finalized head", 90, big.NewInt(110), nil, FinalitySafe, false},
+		{"no heads", 90, nil, nil, FinalityLatest, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewFinality(big.NewInt(tt.blockNumber), tt.safe, tt.finalized)
+			if got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+			if got.IsStable() != tt.wantStable {
+				t.Fatalf("got stable %t, want %t", got.IsStable(), tt.wantStable)
+			}
+			isStable, finality := FinalityStability(tt.safe, tt.finalized)(big.NewInt(tt.blockNumber))
+			if finality != tt.want || isStable != tt.wantStable {
+				t.Fatalf("stability got %t %s, want %t %s", isStable, finality, tt.wantStable, tt.want)
+			}
+		})
+	}
+}
+
+func TestDepthStability(t *testing.T) {
+	tests := []struct {
+		name           string
+		head           int64
+		unstableNumber int
+		blockNumber    int64
+		want           bool
+	}{
+		{"deep", 100, 20, 50, true},
+		{"at stable head", 100, 20, 80, true},
+		{"after stable head", 100, 20, 81, false},
+		{"head", 100, 20, 100, false},
+		{"after head", 100, 20, 101, false},
+		{"no unstable block", 100, 0, 100, true},
+		{"head shorter than unstable number", 10, 20, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isStable, finality := DepthStability(big.NewInt(tt.head), tt.unstableNumber)(big.NewInt(tt.blockNumber))
+			if isStable != tt.want {
+				t.Fatalf("got stable %t, want %t", isStable, tt.want)
+			}
+			if finality != "" {
+				t.Fatalf("got finality %s, want empty", finality)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// OrphanedBlock is a block that was replaced by a chain reorganization
+type OrphanedBlock struct {
+	ID          int64                 `json:"id" gorm:"primaryKey"`
+	BlockNumber GormBigInt            `json:"block_num" gorm:"type:varchar(32);column:block_num;index;uniqueIndex:idx_orphaned_block_hash"`
+	BlockHash   string                `json:"block_hash" gorm:"type:varchar(128);column:block_hash;index;uniqueIndex:idx_orphaned_block_hash"`
+	BlockTime   uint64                `json:"block_time"`
+	ParentHash  string                `json:"parent_hash" gorm:"type:varchar(128);column:parent_hash"`
+	ReplacedBy  string                `json:"replaced_by" gorm:"type:varchar(128);column:replaced_by"`
+	Depth       int                   `json:"depth"`
+	Block       OrphanedBlockSnapshot `json:"block"`
+	DetectedAt  time.Time             `json:"detected_at" gorm:"index"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// OnConflict keeps the orphan archived first
+func (OrphanedBlock) OnConflict(db *gorm.DB) *gorm.DB {
+	return db.Clauses(clause.OnConflict{
+		DoNothing: true,
+	})
+}
+
+// OrphanedBlockSnapshot keeps the replaced block with its transactions and logs
+type OrphanedBlockSnapshot Block
+
+func (s *OrphanedBlockSnapshot) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New(fmt.Sprint("failed convert value to []byte", value))
+	}
+	block := Block{}
+	if err := json.Unmarshal(data, &block); err != nil {
+		return err
+	}
+	*s = OrphanedBlockSnapshot(block)
+	return nil
+}
+
+func (s OrphanedBlockSnapshot) Value() (driver.Value, error) {
+	b, err := json.Marshal(Block(s))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (OrphanedBlockSnapshot) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "mysql":
+		return "longtext"
+	default:
+		return "text"
+	}
+}
@@ -1,24 +1,42 @@
 package model
 
 import (
+	"fmt"
+	"math/big"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type Transaction struct {
-	TXHash      string            `json:"tx_hash" gorm:"type:varchar(128);column:tx_hash;primaryKey;autoIncrement:false"`
-	BlockNumber GormBigInt        `json:"block_num" gorm:"type:varchar(32);column:block_num;index"`
-	From        string            `json:"from" gorm:"type:varchar(128)"`
-	To          string            `json:"to" gorm:"type:varchar(128)"`
-	Nonce       uint64            `json:"nonce"`
-	Data        BlockData         `json:"data"`
-	Value       GormBigInt        `json:"value" gorm:"type:varchar(32)"`
-	Logs        []*TransactionLog `json:"logs" gorm:"foreignKey:TXHash;references:TXHash"`
-	CreatedAt   time.Time         `json:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at"`
-	DeletedAt   *time.Time        `json:"deleted_at" gorm:"index"`
+	TXHash            string            `json:"tx_hash" gorm:"type:varchar(128);column:tx_hash;primaryKey;autoIncrement:false"`
+	BlockNumber       GormBigInt        `json:"block_num" gorm:"type:varchar(32);column:block_num;index"`
+	BlockHeight       int64             `json:"-" gorm:"index:idx_transaction_height_index"`
+	TxIndex           uint              `json:"tx_index" gorm:"index:idx_transaction_height_index"`
+	From              string            `json:"from" gorm:"type:varchar(128);index:idx_transaction_from"`
+	To                string            `json:"to" gorm:"type:varchar(128);index:idx_transaction_to"`
+	Nonce             uint64            `json:"nonce"`
+	Data              BlockData         `json:"data"`
+	Value             GormBigInt        `json:"value" gorm:"type:varchar(32)"`
+	Type              uint8             `json:"type"`
+	Gas               uint64            `json:"gas"`
+	GasPrice          GormBigInt        `json:"gas_price" gorm:"type:varchar(32)"`
+	GasTipCap         *GormBigInt       `json:"gas_tip_cap" gorm:"type:varchar(32)"`
+	GasFeeCap         *GormBigInt       `json:"gas_fee_cap" gorm:"type:varchar(32)"`
+	AccessList        JSON              `json:"access_list"`
+	BlobGasFeeCap     *GormBigInt       `json:"blob_gas_fee_cap" gorm:"type:varchar(32)"`
+	BlobHashes        JSON              `json:"blob_hashes"`
+	Status            uint64            `json:"status"`
+	GasUsed           uint64            `json:"gas_used"`
+	CumulativeGasUsed uint64            `json:"cumulative_gas_used"`
+	EffectiveGasPrice GormBigInt        `json:"effective_gas_price" gorm:"type:varchar(32)"`
+	ContractAddress   string            `json:"contract_address" gorm:"type:varchar(128)"`
+	Logs              []*TransactionLog `json:"logs" gorm:"foreignKey:TXHash;references:TXHash"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+	DeletedAt         *time.Time        `json:"deleted_at" gorm:"index"`
 }
 
 func (tx Transaction) Preload(db *gorm.DB) *gorm.DB {
@@ -26,25 +44,147 @@ func (tx Transaction) Preload(db *gorm.DB) *gorm.DB {
 }
 
 func (transation *Transaction) BeforeCreate(tx *gorm.DB) (err error) {
+	transation.BlockHeight = BlockHeight(transation.BlockNumber.BigInt())
 	tx.Statement.AddClause(clause.OnConflict{
 		UpdateAll: true,
 	})
 	return nil
 }
 
+type TransactionDirection string
+
+const (
+	TransactionDirectionIn  TransactionDirection = "in"
+	TransactionDirectionOut TransactionDirection = "out"
+	TransactionDirectionAll TransactionDirection = "all"
+)
+
+// TransactionCursor points to the last transaction of the previous page,
+// transactions are ordered by block number, tx index and tx hash descending
+type TransactionCursor struct {
+	BlockNumber *big.Int
+	TxIndex     uint
+	TXHash      string
+}
+
+func (cursor TransactionCursor) Where(db *gorm.DB) *gorm.DB {
+	height := BlockHeight(cursor.BlockNumber)
+	return db.Where(
+		"(block_height < ? OR (block_height = ? AND (tx_index < ? OR (tx_index = ? AND tx_hash < ?))))",
+		height, height, cursor.TxIndex, cursor.TxIndex, cursor.TXHash,
+	)
+}
+
+// AddressFilter filters the transactions sent from or to an address
+type AddressFilter struct {
+	BlockRange
+	Address   string
+	Direction TransactionDirection
+	Cursor    *TransactionCursor
+}
+
+func (filter AddressFilter) Where(db *gorm.DB) *gorm.DB {
+	switch filter.Direction {
+	case TransactionDirectionIn:
+		db = db.Where(clause.Eq{Column: clause.Column{Name: "to"}, Value: filter.Address})
+	case TransactionDirectionOut:
+		db = db.Where(clause.Eq{Column: clause.Column{Name: "from"}, Value: filter.Address})
+	default:
+		db = db.Where(clause.Or(
+			clause.Eq{Column: clause.Column{Name: "from"}, Value: filter.Address},
+			clause.Eq{Column: clause.Column{Name: "to"}, Value: filter.Address},
+		))
+	}
+	db = filter.BlockRange.Where(db)
+	if filter.Cursor != nil {
+		db = filter.Cursor.Where(db)
+	}
+	return db
+}
+
+// Sort orders the transactions from the latest
+func (tx Transaction) Sort(db *gorm.DB) *gorm.DB {
+	db = BlockNumberSorting(SortDESC).Sort(db)
+	return db.Order("tx_index DESC").Order("tx_hash DESC")
+}
+
 type TransactionLog struct {
-	ID        int64      `json:"id" gorm:"primaryKey"`
-	TXHash    string     `json:"tx_hash" gorm:"type:varchar(128)column:tx_hash;index"`
-	Index     uint64     `json:"index"`
-	Data      BlockData  `json:"data"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at" gorm:"index"`
+	ID          int64      `json:"id" gorm:"primaryKey"`
+	TXHash      string     `json:"tx_hash" gorm:"type:varchar(128);column:tx_hash;uniqueIndex:idx_log_tx_hash_index"`
+	TxIndex     uint       `json:"tx_index"`
+	BlockNumber GormBigInt `json:"block_num" gorm:"type:varchar(32);column:block_num;index"`
+	BlockHeight int64      `json:"-" gorm:"index:idx_log_height_index"`
+	Address     string     `json:"address" gorm:"type:varchar(128);index"`
+	Topic0      string     `json:"topic0" gorm:"type:varchar(128);column:topic0;index"`
+	Topic1      string     `json:"topic1" gorm:"type:varchar(128);column:topic1;index"`
+	Topic2      string     `json:"topic2" gorm:"type:varchar(128);column:topic2;index"`
+	Topic3      string     `json:"topic3" gorm:"type:varchar(128);column:topic3;index"`
+	Index       uint64     `json:"index" gorm:"uniqueIndex:idx_log_tx_hash_index;index:idx_log_height_index"`
+	Data        BlockData  `json:"data"`
+	Removed     bool       `json:"removed"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at" gorm:"index"`
+}
+
+// SetTopics fills the topic columns, a log has at most four topics
+func (log *TransactionLog) SetTopics(topics []common.Hash) {
+	columns := []*string{&log.Topic0, &log.Topic1, &log.Topic2, &log.Topic3}
+	for i, topic := range topics {
+		if i >= len(columns) {
+			break
+		}
+		*columns[i] = topic.Hex()
+	}
+}
+
+// Topics returns the topics of the log in order
+func (log TransactionLog) Topics() []string {
+	topics := []string{}
+	for _, topic := range []string{log.Topic0, log.Topic1, log.Topic2, log.Topic3} {
+		if len(topic) == 0 {
+			break
+		}
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// Sort orders the logs as they are emitted on chain
+func (log TransactionLog) Sort(db *gorm.DB) *gorm.DB {
+	db = BlockNumberSorting(SortASC).Sort(db)
+	return db.Order(clause.OrderByColumn{Column: clause.Column{Name: "index"}})
 }
 
 func (log *TransactionLog) BeforeCreate(tx *gorm.DB) (err error) {
+	log.BlockHeight = BlockHeight(log.BlockNumber.BigInt())
 	tx.Statement.AddClause(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tx_hash"}, {Name: "index"}},
 		UpdateAll: true,
 	})
 	return nil
 }
+
+// LogFilter filters logs like eth_getLogs, addresses and the topics of each position are OR-ed,
+// the positions are AND-ed and an empty position matches any topic
+type LogFilter struct {
+	BlockRange
+	Addresses []string   `json:"addresses,omitempty"`
+	Topics    [][]string `json:"topics,omitempty"`
+}
+
+func (filter LogFilter) Where(db *gorm.DB) *gorm.DB {
+	db = filter.BlockRange.Where(db)
+	if len(filter.Addresses) > 0 {
+		db = db.Where("address IN ?", filter.Addresses)
+	}
+	for i, topics := range filter.Topics {
+		if i >= 4 {
+			break
+		}
+		if len(topics) > 0 {
+			db = db.Where(fmt.Sprintf("topic%d IN ?", i), topics)
+		}
+	}
+	return db
+}
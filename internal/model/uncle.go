@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Uncle is an ommer header included by the block
+type Uncle struct {
+	ID          int64      `json:"id" gorm:"primaryKey"`
+	BlockNumber GormBigInt `json:"block_num" gorm:"type:varchar(32);column:block_num;uniqueIndex:idx_uncle_block_position"`
+	Position    int        `json:"position" gorm:"uniqueIndex:idx_uncle_block_position"`
+	UncleHash   string     `json:"uncle_hash" gorm:"type:varchar(128);index"`
+	UncleNumber GormBigInt `json:"uncle_num" gorm:"type:varchar(32);column:uncle_num"`
+	ParentHash  string     `json:"parent_hash" gorm:"type:varchar(128)"`
+	Coinbase    string     `json:"coinbase" gorm:"type:varchar(128)"`
+	Difficulty  GormBigInt `json:"difficulty" gorm:"type:varchar(32)"`
+	GasLimit    uint64     `json:"gas_limit"`
+	GasUsed     uint64     `json:"gas_used"`
+	UncleTime   uint64     `json:"uncle_time"`
+	ExtraData   BlockData  `json:"extra_data"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (uncle Uncle) Sort(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
+func (uncle *Uncle) BeforeCreate(tx *gorm.DB) (err error) {
+	tx.Statement.AddClause(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "block_num"}, {Name: "position"}},
+		UpdateAll: true,
+	})
+	return nil
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Withdrawal is a validator withdrawal (EIP-4895) included by the block
+type Withdrawal struct {
+	ID             int64      `json:"id" gorm:"primaryKey"`
+	BlockNumber    GormBigInt `json:"block_num" gorm:"type:varchar(32);column:block_num;index"`
+	BlockHeight    int64      `json:"-" gorm:"index"`
+	Index          uint64     `json:"index" gorm:"column:withdrawal_index;uniqueIndex"`
+	ValidatorIndex uint64     `json:"validator_index" gorm:"index"`
+	Address        string     `json:"address" gorm:"type:varchar(128);index"`
+	Amount         uint64     `json:"amount"` // gwei
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Sort orders the withdrawals from the latest
+func (withdrawal Withdrawal) Sort(db *gorm.DB) *gorm.DB {
+	return db.Order("withdrawal_index DESC")
+}
+
+func (withdrawal *Withdrawal) BeforeCreate(tx *gorm.DB) (err error) {
+	withdrawal.BlockHeight = BlockHeight(withdrawal.BlockNumber.BigInt())
+	tx.Statement.AddClause(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "withdrawal_index"}},
+		UpdateAll: true,
+	})
+	return nil
+}
@@ -3,28 +3,87 @@ package migration
 import (
 	"math/big"
 	"sync-ethereum/internal/model"
+	"time"
 
 	"github.com/go-gormigrate/gormigrate/v2"
 	"gorm.io/gorm"
 )
 
+type v202105221650Block struct {
+	BlockNumber model.GormBigInt `gorm:"type:varchar(32);column:block_num;primaryKey;autoIncrement:false"`
+	BlockHash   string           `gorm:"type:varchar(128);column:block_hash;uniqueIndex:idx_block_parent_hash"`
+	BlockTime   uint64
+	ParentHash  string `gorm:"type:varchar(128);column:parent_hash;uniqueIndex:idx_block_parent_hash"`
+	IsStable    bool
+	Transaction []*v202105221650Transaction `gorm:"foreignKey:BlockNumber;references:BlockNumber"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time `gorm:"index"`
+}
+
+func (v202105221650Block) TableName() string {
+	return "blocks"
+}
+
+type v202105221650Transaction struct {
+	TXHash      string           `gorm:"type:varchar(128);column:tx_hash;primaryKey;autoIncrement:false"`
+	BlockNumber model.GormBigInt `gorm:"type:varchar(32);column:block_num;index"`
+	From        string           `gorm:"type:varchar(128)"`
+	To          string           `gorm:"type:varchar(128)"`
+	Nonce       uint64
+	Data        model.BlockData
+	Value       model.GormBigInt               `gorm:"type:varchar(32)"`
+	Logs        []*v202105221650TransactionLog `gorm:"foreignKey:TXHash;references:TXHash"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time `gorm:"index"`
+}
+
+func (v202105221650Transaction) TableName() string {
+	return "transactions"
+}
+
+type v202105221650TransactionLog struct {
+	ID        int64  `gorm:"primaryKey"`
+	TXHash    string `gorm:"type:varchar(128);column:tx_hash;index"`
+	Index     uint64
+	Data      model.BlockData
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time `gorm:"index"`
+}
+
+func (v202105221650TransactionLog) TableName() string {
+	return "transaction_logs"
+}
+
+type v202105221650CurrentBlockNumber struct {
+	ID                int64            `gorm:"primaryKey"`
+	BlockNumber       model.GormBigInt `gorm:"type:varchar(32);column:block_num"`
+	OnlineBlockNumber model.GormBigInt `gorm:"type:varchar(32);column:online_block_num"`
+}
+
+func (v202105221650CurrentBlockNumber) TableName() string {
+	return "current_block_numbers"
+}
+
 var v202105221650 = &gormigrate.Migration{
 	ID: "202105221650",
 	Migrate: func(tx *gorm.DB) error {
-		if err := tx.AutoMigrate(&model.Block{}); err != nil {
+		if err := tx.AutoMigrate(&v202105221650Block{}); err != nil {
 			return err
 		}
-		if err := tx.AutoMigrate(&model.Transaction{}); err != nil {
+		if err := tx.AutoMigrate(&v202105221650Transaction{}); err != nil {
 			return err
 		}
-		if err := tx.AutoMigrate(&model.TransactionLog{}); err != nil {
+		if err := tx.AutoMigrate(&v202105221650TransactionLog{}); err != nil {
 			return err
 		}
-		if err := tx.AutoMigrate(&model.CurrentBlockNumber{}); err != nil {
+		if err := tx.AutoMigrate(&v202105221650CurrentBlockNumber{}); err != nil {
 			return err
 		}
 		blockNumber := big.NewInt(0)
-		if err := tx.Create(&model.CurrentBlockNumber{
+		if err := tx.Create(&v202105221650CurrentBlockNumber{
 			BlockNumber: model.GormBigInt(*blockNumber),
 		}).Error; err != nil {
 			return err
@@ -32,16 +91,16 @@ var v202105221650 = &gormigrate.Migration{
 		return nil
 	},
 	Rollback: func(tx *gorm.DB) error {
-		if err := tx.Migrator().DropTable(&model.Block{}); err != nil {
+		if err := tx.Migrator().DropTable(&v202105221650Block{}); err != nil {
 			return err
 		}
-		if err := tx.Migrator().DropTable(&model.Transaction{}); err != nil {
+		if err := tx.Migrator().DropTable(&v202105221650Transaction{}); err != nil {
 			return err
 		}
-		if err := tx.Migrator().DropTable(&model.TransactionLog{}); err != nil {
+		if err := tx.Migrator().DropTable(&v202105221650TransactionLog{}); err != nil {
 			return err
 		}
-		if err := tx.Migrator().DropTable(&model.CurrentBlockNumber{}); err != nil {
+		if err := tx.Migrator().DropTable(&v202105221650CurrentBlockNumber{}); err != nil {
 			return err
 		}
 		return nil
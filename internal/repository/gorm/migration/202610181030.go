@@ -0,0 +1,37 @@
+package migration
+
+import (
+	"sync-ethereum/internal/model"
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type v202610181030OrphanedBlock struct {
+	ID          int64            `gorm:"primaryKey"`
+	BlockNumber model.GormBigInt `gorm:"type:varchar(32);column:block_num;index"`
+	BlockHash   string           `gorm:"type:varchar(128);column:block_hash;index"`
+	BlockTime   uint64
+	ParentHash  string `gorm:"type:varchar(128);column:parent_hash"`
+	ReplacedBy  string `gorm:"type:varchar(128);column:replaced_by"`
+	Depth       int
+	Block       model.OrphanedBlockSnapshot
+	DetectedAt  time.Time `gorm:"index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (v202610181030OrphanedBlock) TableName() string {
+	return "orphaned_blocks"
+}
+
+var v202610181030 = &gormigrate.Migration{
+	ID: "202610181030",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&v202610181030OrphanedBlock{})
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&v202610181030OrphanedBlock{})
+	},
+}
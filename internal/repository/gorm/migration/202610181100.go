@@ -0,0 +1,31 @@
+package migration
+
+import (
+	"sync-ethereum/internal/model"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type v202610181100Transaction struct {
+	Type              uint8
+	Status            uint64
+	GasUsed           uint64
+	CumulativeGasUsed uint64
+	EffectiveGasPrice model.GormBigInt `gorm:"type:varchar(32)"`
+	ContractAddress   string           `gorm:"type:varchar(128)"`
+}
+
+func (v202610181100Transaction) TableName() string {
+	return "transactions"
+}
+
+var v202610181100 = &gormigrate.Migration{
+	ID: "202610181100",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&v202610181100Transaction{})
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return _DropColumns(tx, &v202610181100Transaction{}, "Type", "Status", "GasUsed", "CumulativeGasUsed", "EffectiveGasPrice", "ContractAddress")
+	},
+}
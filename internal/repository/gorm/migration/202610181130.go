@@ -0,0 +1,43 @@
+package migration
+
+import (
+	"sync-ethereum/internal/model"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type v202610181130TransactionLog struct {
+	TXHash      string `gorm:"type:varchar(128);column:tx_hash;uniqueIndex:idx_log_tx_hash_index"`
+	TxIndex     uint
+	BlockNumber model.GormBigInt `gorm:"type:varchar(32);column:block_num;index"`
+	Address     string           `gorm:"type:varchar(128);index"`
+	Topic0      string           `gorm:"type:varchar(128);column:topic0;index"`
+	Topic1      string           `gorm:"type:varchar(128);column:topic1;index"`
+	Topic2      string           `gorm:"type:varchar(128);column:topic2;index"`
+	Topic3      string           `gorm:"type:varchar(128);column:topic3;index"`
+	Index       uint64           `gorm:"uniqueIndex:idx_log_tx_hash_index"`
+	Removed     bool
+}
+
+func (v202610181130TransactionLog) TableName() string {
+	return "transaction_logs"
+}
+
+var v202610181130 = &gormigrate.Migration{
+	ID: "202610181130",
+	Migrate: func(tx *gorm.DB) error {
+		// keep the latest copy of the logs which were duplicated by recrawling before the unique index exists
+		latest := tx.Model(&v202610181130TransactionLog{}).Select("MAX(id)").Group("tx_hash").Group("index")
+		if err := tx.Where("id NOT IN (?)", tx.Table("(?) AS latest", latest)).Delete(&v202610181130TransactionLog{}).Error; err != nil {
+			return err
+		}
+		return tx.AutoMigrate(&v202610181130TransactionLog{})
+	},
+	Rollback: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropIndex(&v202610181130TransactionLog{}, "idx_log_tx_hash_index"); err != nil {
+			return err
+		}
+		return _DropColumns(tx, &v202610181130TransactionLog{}, "TxIndex", "BlockNumber", "Address", "Topic0", "Topic1", "Topic2", "Topic3", "Removed")
+	},
+}
@@ -0,0 +1,31 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type v202610181200Transaction struct {
+	TxIndex uint
+	From    string `gorm:"type:varchar(128);index:idx_transaction_from"`
+	To      string `gorm:"type:varchar(128);index:idx_transaction_to"`
+}
+
+func (v202610181200Transaction) TableName() string {
+	return "transactions"
+}
+
+var v202610181200 = &gormigrate.Migration{
+	ID: "202610181200",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&v202610181200Transaction{})
+	},
+	Rollback: func(tx *gorm.DB) error {
+		for _, index := range []string{"idx_transaction_from", "idx_transaction_to"} {
+			if err := tx.Migrator().DropIndex(&v202610181200Transaction{}, index); err != nil {
+				return err
+			}
+		}
+		return _DropColumns(tx, &v202610181200Transaction{}, "TxIndex")
+	},
+}
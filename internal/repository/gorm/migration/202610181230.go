@@ -0,0 +1,38 @@
+package migration
+
+import (
+	"sync-ethereum/internal/model"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type v202610181230Block struct {
+	Coinbase    string `gorm:"type:varchar(128)"`
+	GasLimit    uint64
+	GasUsed     uint64
+	BaseFee     *model.GormBigInt `gorm:"type:varchar(32)"`
+	Difficulty  model.GormBigInt  `gorm:"type:varchar(32)"`
+	Nonce       uint64
+	MixDigest   string `gorm:"type:varchar(128)"`
+	StateRoot   string `gorm:"type:varchar(128)"`
+	TxRoot      string `gorm:"type:varchar(128)"`
+	ReceiptRoot string `gorm:"type:varchar(128)"`
+	ExtraData   model.BlockData
+	Size        uint64
+	UncleCount  int
+}
+
+func (v202610181230Block) TableName() string {
+	return "blocks"
+}
+
+var v202610181230 = &gormigrate.Migration{
+	ID: "202610181230",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&v202610181230Block{})
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return _DropColumns(tx, &v202610181230Block{}, "Coinbase", "GasLimit", "GasUsed", "BaseFee", "Difficulty", "Nonce", "MixDigest", "StateRoot", "TxRoot", "ReceiptRoot", "ExtraData", "Size", "UncleCount")
+	},
+}
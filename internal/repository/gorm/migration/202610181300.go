@@ -0,0 +1,32 @@
+package migration
+
+import (
+	"sync-ethereum/internal/model"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type v202610181300Transaction struct {
+	Gas           uint64
+	GasPrice      model.GormBigInt  `gorm:"type:varchar(32)"`
+	GasTipCap     *model.GormBigInt `gorm:"type:varchar(32)"`
+	GasFeeCap     *model.GormBigInt `gorm:"type:varchar(32)"`
+	AccessList    model.JSON
+	BlobGasFeeCap *model.GormBigInt `gorm:"type:varchar(32)"`
+	BlobHashes    model.JSON
+}
+
+func (v202610181300Transaction) TableName() string {
+	return "transactions"
+}
+
+var v202610181300 = &gormigrate.Migration{
+	ID: "202610181300",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&v202610181300Transaction{})
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return _DropColumns(tx, &v202610181300Transaction{}, "Gas", "GasPrice", "GasTipCap", "GasFeeCap", "AccessList", "BlobGasFeeCap", "BlobHashes")
+	},
+}
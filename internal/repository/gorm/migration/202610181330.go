@@ -0,0 +1,50 @@
+package migration
+
+import (
+	"sync-ethereum/internal/model"
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// v202610181330Block is only the relation of the uncles, it gives the uncles table the foreign key of the blocks
+type v202610181330Block struct {
+	BlockNumber model.GormBigInt      `gorm:"type:varchar(32);column:block_num;primaryKey;autoIncrement:false"`
+	Uncles      []*v202610181330Uncle `gorm:"foreignKey:BlockNumber;references:BlockNumber"`
+}
+
+func (v202610181330Block) TableName() string {
+	return "blocks"
+}
+
+type v202610181330Uncle struct {
+	ID          int64            `gorm:"primaryKey"`
+	BlockNumber model.GormBigInt `gorm:"type:varchar(32);column:block_num;uniqueIndex:idx_uncle_block_position"`
+	Position    int              `gorm:"uniqueIndex:idx_uncle_block_position"`
+	UncleHash   string           `gorm:"type:varchar(128);index"`
+	UncleNumber model.GormBigInt `gorm:"type:varchar(32);column:uncle_num"`
+	ParentHash  string           `gorm:"type:varchar(128)"`
+	Coinbase    string           `gorm:"type:varchar(128)"`
+	Difficulty  model.GormBigInt `gorm:"type:varchar(32)"`
+	GasLimit    uint64
+	GasUsed     uint64
+	UncleTime   uint64
+	ExtraData   model.BlockData
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (v202610181330Uncle) TableName() string {
+	return "uncles"
+}
+
+var v202610181330 = &gormigrate.Migration{
+	ID: "202610181330",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&v202610181330Block{}, &v202610181330Uncle{})
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&v202610181330Uncle{})
+	},
+}
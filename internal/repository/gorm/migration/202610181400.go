@@ -0,0 +1,44 @@
+package migration
+
+import (
+	"sync-ethereum/internal/model"
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// v202610181400Block is only the relation of the withdrawals, it gives the withdrawals table the foreign key of the blocks
+type v202610181400Block struct {
+	BlockNumber model.GormBigInt           `gorm:"type:varchar(32);column:block_num;primaryKey;autoIncrement:false"`
+	Withdrawals []*v202610181400Withdrawal `gorm:"foreignKey:BlockNumber;references:BlockNumber"`
+}
+
+func (v202610181400Block) TableName() string {
+	return "blocks"
+}
+
+type v202610181400Withdrawal struct {
+	ID             int64            `gorm:"primaryKey"`
+	BlockNumber    model.GormBigInt `gorm:"type:varchar(32);column:block_num;index"`
+	Index          uint64           `gorm:"column:withdrawal_index;uniqueIndex"`
+	ValidatorIndex uint64           `gorm:"index"`
+	Address        string           `gorm:"type:varchar(128);index"`
+	Amount         uint64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (v202610181400Withdrawal) TableName() string {
+	return "withdrawals"
+}
+
+var v202610181400 = &gormigrate.Migration{
+	ID: "202610181400",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&v202610181400Block{}, &v202610181400Withdrawal{})
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&v202610181400Withdrawal{})
+	},
+}
@@ -0,0 +1,41 @@
+package migration
+
+import (
+	"sync-ethereum/internal/model"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type v202610181430Block struct {
+	Finality model.Finality `gorm:"type:varchar(16)"`
+}
+
+func (v202610181430Block) TableName() string {
+	return "blocks"
+}
+
+type v202610181430CurrentBlockNumber struct {
+	SafeBlockNumber      model.GormBigInt `gorm:"type:varchar(32);column:safe_block_num"`
+	FinalizedBlockNumber model.GormBigInt `gorm:"type:varchar(32);column:finalized_block_num"`
+}
+
+func (v202610181430CurrentBlockNumber) TableName() string {
+	return "current_block_numbers"
+}
+
+var v202610181430 = &gormigrate.Migration{
+	ID: "202610181430",
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&v202610181430Block{}); err != nil {
+			return err
+		}
+		return tx.AutoMigrate(&v202610181430CurrentBlockNumber{})
+	},
+	Rollback: func(tx *gorm.DB) error {
+		if err := _DropColumns(tx, &v202610181430Block{}, "Finality"); err != nil {
+			return err
+		}
+		return _DropColumns(tx, &v202610181430CurrentBlockNumber{}, "SafeBlockNumber", "FinalizedBlockNumber")
+	},
+}
@@ -0,0 +1,33 @@
+package migration
+
+import (
+	"sync-ethereum/internal/model"
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type v202610181500BackfillSegment struct {
+	ID              int64                `gorm:"primaryKey"`
+	FromBlockNumber model.GormBigInt     `gorm:"type:varchar(32);column:from_block_num;uniqueIndex:idx_backfill_segment_range"`
+	ToBlockNumber   model.GormBigInt     `gorm:"type:varchar(32);column:to_block_num;uniqueIndex:idx_backfill_segment_range"`
+	NextBlockNumber model.GormBigInt     `gorm:"type:varchar(32);column:next_block_num"`
+	Status          model.BackfillStatus `gorm:"type:varchar(16);index"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (v202610181500BackfillSegment) TableName() string {
+	return "backfill_segments"
+}
+
+var v202610181500 = &gormigrate.Migration{
+	ID: "202610181500",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&v202610181500BackfillSegment{})
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&v202610181500BackfillSegment{})
+	},
+}
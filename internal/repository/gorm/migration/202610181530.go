@@ -0,0 +1,32 @@
+package migration
+
+import (
+	"sync-ethereum/internal/model"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type v202610181530OrphanedBlock struct {
+	BlockNumber model.GormBigInt `gorm:"type:varchar(32);column:block_num;uniqueIndex:idx_orphaned_block_hash"`
+	BlockHash   string           `gorm:"type:varchar(128);column:block_hash;uniqueIndex:idx_orphaned_block_hash"`
+}
+
+func (v202610181530OrphanedBlock) TableName() string {
+	return "orphaned_blocks"
+}
+
+var v202610181530 = &gormigrate.Migration{
+	ID: "202610181530",
+	Migrate: func(tx *gorm.DB) error {
+		// keep the first copy of the orphans which were archived twice before the unique index exists
+		first := tx.Model(&v202610181530OrphanedBlock{}).Select("MIN(id)").Group("block_num").Group("block_hash")
+		if err := tx.Where("id NOT IN (?)", tx.Table("(?) AS first", first)).Delete(&v202610181530OrphanedBlock{}).Error; err != nil {
+			return err
+		}
+		return tx.Migrator().CreateIndex(&v202610181530OrphanedBlock{}, "idx_orphaned_block_hash")
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Migrator().DropIndex(&v202610181530OrphanedBlock{}, "idx_orphaned_block_hash")
+	},
+}
@@ -0,0 +1,93 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type v202610181600Block struct {
+	BlockHeight int64 `gorm:"index"`
+}
+
+func (v202610181600Block) TableName() string {
+	return "blocks"
+}
+
+type v202610181600Transaction struct {
+	BlockHeight int64 `gorm:"index:idx_transaction_height_index"`
+	TxIndex     uint  `gorm:"index:idx_transaction_height_index"`
+}
+
+func (v202610181600Transaction) TableName() string {
+	return "transactions"
+}
+
+type v202610181600TransactionLog struct {
+	BlockHeight int64  `gorm:"index:idx_log_height_index"`
+	Index       uint64 `gorm:"index:idx_log_height_index"`
+}
+
+func (v202610181600TransactionLog) TableName() string {
+	return "transaction_logs"
+}
+
+type v202610181600Withdrawal struct {
+	BlockHeight int64 `gorm:"index"`
+}
+
+func (v202610181600Withdrawal) TableName() string {
+	return "withdrawals"
+}
+
+var v202610181600 = &gormigrate.Migration{
+	ID: "202610181600",
+	Migrate: func(tx *gorm.DB) error {
+		integer := "BIGINT"
+		switch tx.Dialector.Name() {
+		case "mysql":
+			integer = "SIGNED"
+		case "sqlite":
+			integer = "INTEGER"
+		}
+		for _, m := range []struct {
+			value interface{}
+			index string
+		}{
+			{&v202610181600Block{}, "BlockHeight"},
+			{&v202610181600Transaction{}, "idx_transaction_height_index"},
+			{&v202610181600TransactionLog{}, "idx_log_height_index"},
+			{&v202610181600Withdrawal{}, "BlockHeight"},
+		} {
+			if err := tx.Migrator().AddColumn(m.value, "BlockHeight"); err != nil {
+				return err
+			}
+			// the index is created after the existing rows are filled
+			if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Model(m.value).Update("block_height", gorm.Expr("CAST(block_num AS "+integer+")")).Error; err != nil {
+				return err
+			}
+			if err := tx.Migrator().CreateIndex(m.value, m.index); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Rollback: func(tx *gorm.DB) error {
+		for _, m := range []struct {
+			value interface{}
+			index string
+		}{
+			{&v202610181600Block{}, "BlockHeight"},
+			{&v202610181600Transaction{}, "idx_transaction_height_index"},
+			{&v202610181600TransactionLog{}, "idx_log_height_index"},
+			{&v202610181600Withdrawal{}, "BlockHeight"},
+		} {
+			if err := tx.Migrator().DropIndex(m.value, m.index); err != nil {
+				return err
+			}
+			if err := _DropColumns(tx, m.value, "BlockHeight"); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,41 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+type v202610181630BackfillSegment struct {
+	FromBlockHeight int64 `gorm:"index"`
+}
+
+func (v202610181630BackfillSegment) TableName() string {
+	return "backfill_segments"
+}
+
+var v202610181630 = &gormigrate.Migration{
+	ID: "202610181630",
+	Migrate: func(tx *gorm.DB) error {
+		integer := "BIGINT"
+		switch tx.Dialector.Name() {
+		case "mysql":
+			integer = "SIGNED"
+		case "sqlite":
+			integer = "INTEGER"
+		}
+		if err := tx.Migrator().AddColumn(&v202610181630BackfillSegment{}, "FromBlockHeight"); err != nil {
+			return err
+		}
+		// the index is created after the existing rows are filled
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Model(&v202610181630BackfillSegment{}).Update("from_block_height", gorm.Expr("CAST(from_block_num AS "+integer+")")).Error; err != nil {
+			return err
+		}
+		return tx.Migrator().CreateIndex(&v202610181630BackfillSegment{}, "FromBlockHeight")
+	},
+	Rollback: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropIndex(&v202610181630BackfillSegment{}, "FromBlockHeight"); err != nil {
+			return err
+		}
+		return _DropColumns(tx, &v202610181630BackfillSegment{}, "FromBlockHeight")
+	},
+}
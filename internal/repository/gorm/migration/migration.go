@@ -1,8 +1,86 @@
 package migration
 
-import "github.com/go-gormigrate/gormigrate/v2"
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
 
-// Migrations is a collection of storage migration patterns
+// Migrations is a collection of storage migration patterns,
+// a migration works on its own snapshot of the models instead of the live models,
+// so it creates only the schema of its version whatever the models become later
 var Migrations = []*gormigrate.Migration{
 	v202105221650,
+	v202610181030,
+	v202610181100,
+	v202610181130,
+	v202610181200,
+	v202610181230,
+	v202610181300,
+	v202610181330,
+	v202610181400,
+	v202610181430,
+	v202610181500,
+	v202610181530,
+	v202610181600,
+	v202610181630,
+}
+
+type _Index struct {
+	Name string
+	SQL  string
+}
+
+// _DropColumns drops the columns of a snapshot, sqlite drops a column by rebuilding the table without its indexes,
+// so the indexes of the remaining columns are created again
+func _DropColumns(tx *gorm.DB, value interface{}, columns ...string) error {
+	if tx.Dialector.Name() != "sqlite" {
+		for _, column := range columns {
+			if err := tx.Migrator().DropColumn(value, column); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(value); err != nil {
+		return err
+	}
+	indexes := []*_Index{}
+	if err := tx.Raw("SELECT name, sql FROM sqlite_master WHERE type = ? AND tbl_name = ? AND sql IS NOT NULL", "index", stmt.Table).Scan(&indexes).Error; err != nil {
+		return err
+	}
+	indexColumns := make(map[string][]string, len(indexes))
+	for _, index := range indexes {
+		columns := []string{}
+		if err := tx.Raw("SELECT name FROM pragma_index_info(?)", index.Name).Scan(&columns).Error; err != nil {
+			return err
+		}
+		indexColumns[index.Name] = columns
+	}
+
+	dropped := map[string]bool{}
+	for _, column := range columns {
+		if err := tx.Migrator().DropColumn(value, column); err != nil {
+			return err
+		}
+		if field := stmt.Schema.LookUpField(column); field != nil {
+			column = field.DBName
+		}
+		dropped[column] = true
+	}
+
+	for _, index := range indexes {
+		covered := false
+		for _, column := range indexColumns[index.Name] {
+			covered = covered || dropped[column]
+		}
+		if covered || tx.Migrator().HasIndex(value, index.Name) {
+			continue
+		}
+		if err := tx.Exec(index.SQL).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -32,8 +32,8 @@ func (repo *StorageRepository) MigrateUp() error {
 }
 
 func (repo *StorageRepository) MigrateDown() error {
-	for _, m := range migration.Migrations {
-		if err := repo.migration.RollbackMigration(m); err != nil {
+	for i := len(migration.Migrations) - 1; i >= 0; i-- { // roll back the latest migration first
+		if err := repo.migration.RollbackMigration(migration.Migrations[i]); err != nil {
 			return err
 		}
 	}
@@ -74,6 +74,13 @@ func (repo *StorageRepository) ListBlock(ctx context.Context, filter model.Block
 	return blocks, tx.Error
 }
 
+func (repo *StorageRepository) ListBlockSummary(ctx context.Context, filter model.BlockRange, scope ...func(*gorm.DB) *gorm.DB) ([]model.BlockSummary, error) {
+	summaries := []model.BlockSummary{}
+	txCount := repo.db.Model(model.Transaction{}).Select("COUNT(*)").Where("transactions.block_num = blocks.block_num")
+	tx := repo.db.WithContext(ctx).Scopes(scope...).Model(model.Block{}).Select("block_num, block_hash, parent_hash, (?) AS tx_count", txCount).Scopes(filter.Where).Find(&summaries)
+	return summaries, tx.Error
+}
+
 func (repo *StorageRepository) CreateBlock(ctx context.Context, block *model.Block, scope ...func(*gorm.DB) *gorm.DB) error {
 	return repo.db.WithContext(ctx).Scopes(scope...).Create(block).Error
 }
@@ -82,6 +89,99 @@ func (repo *StorageRepository) UpdateBlock(ctx context.Context, filter model.Blo
 	return repo.db.WithContext(ctx).Scopes(scope...).Where(filter).Updates(block).Error
 }
 
+// OrphanBlocks archives the stored blocks with their transactions, logs, uncles and withdrawals, then removes them,
+// a block is matched by its hash as well, so the canonical block written by another worker in the meantime is kept
+func (repo *StorageRepository) OrphanBlocks(ctx context.Context, orphanedBlocks []*model.OrphanedBlock, scope ...func(*gorm.DB) *gorm.DB) error {
+	return repo.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		blocks := make([]model.Block, 0, len(orphanedBlocks))
+		for _, orphanedBlock := range orphanedBlocks {
+			block := model.Block{}
+			err := tx.Preload("Transaction.Logs").Preload("Uncles").Preload("Withdrawals").Where("block_num = ? AND block_hash = ?", orphanedBlock.BlockNumber, orphanedBlock.BlockHash).First(&block).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			orphanedBlock.BlockTime = block.BlockTime
+			orphanedBlock.ParentHash = block.ParentHash
+			orphanedBlock.Block = model.OrphanedBlockSnapshot(block)
+			// another worker may archive the same orphan of the reorganization
+			if err := tx.Scopes(scope...).Scopes(model.OrphanedBlock{}.OnConflict).Create(orphanedBlock).Error; err != nil {
+				return err
+			}
+			blocks = append(blocks, block)
+		}
+		if len(blocks) == 0 {
+			return nil
+		}
+		return _DeleteBlocks(tx, blocks, scope...)
+	})
+}
+
+// _DeleteBlocks removes the blocks and their transactions, transaction logs, uncles and withdrawals
+func _DeleteBlocks(tx *gorm.DB, blocks []model.Block, scope ...func(*gorm.DB) *gorm.DB) error {
+	blockNumbers := make([]model.GormBigInt, 0, len(blocks))
+	for _, block := range blocks {
+		blockNumbers = append(blockNumbers, block.BlockNumber)
+	}
+	txHashes := tx.Model(&model.Transaction{}).Select("tx_hash").Where("block_num IN ?", blockNumbers)
+	if err := tx.Scopes(scope...).Where("tx_hash IN (?)", txHashes).Delete(&model.TransactionLog{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Scopes(scope...).Where("block_num IN ?", blockNumbers).Delete(&model.Transaction{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Scopes(scope...).Where("block_num IN ?", blockNumbers).Delete(&model.Uncle{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Scopes(scope...).Where("block_num IN ?", blockNumbers).Delete(&model.Withdrawal{}).Error; err != nil {
+		return err
+	}
+	for _, block := range blocks {
+		if err := tx.Scopes(scope...).Where("block_num = ? AND block_hash = ?", block.BlockNumber, block.BlockHash).Delete(&model.Block{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *StorageRepository) ListOrphanedBlock(ctx context.Context, filter model.OrphanedBlock, scope ...func(*gorm.DB) *gorm.DB) ([]model.OrphanedBlock, error) {
+	orphanedBlocks := []model.OrphanedBlock{}
+	tx := repo.db.WithContext(ctx).Scopes(scope...).Model(model.OrphanedBlock{}).Where(filter).Find(&orphanedBlocks)
+	return orphanedBlocks, tx.Error
+}
+
+func (repo *StorageRepository) ListUncle(ctx context.Context, filter model.Uncle, scope ...func(*gorm.DB) *gorm.DB) ([]model.Uncle, error) {
+	uncles := []model.Uncle{}
+	tx := repo.db.WithContext(ctx).Scopes(scope...).Model(model.Uncle{}).Where(filter).Find(&uncles)
+	return uncles, tx.Error
+}
+
+func (repo *StorageRepository) ListWithdrawal(ctx context.Context, filter model.Withdrawal, scope ...func(*gorm.DB) *gorm.DB) ([]model.Withdrawal, error) {
+	withdrawals := []model.Withdrawal{}
+	tx := repo.db.WithContext(ctx).Scopes(scope...).Model(model.Withdrawal{}).Where(filter).Find(&withdrawals)
+	return withdrawals, tx.Error
+}
+
+func (repo *StorageRepository) CreateBackfillSegments(ctx context.Context, segments []*model.BackfillSegment, scope ...func(*gorm.DB) *gorm.DB) error {
+	if len(segments) == 0 {
+		return nil
+	}
+	return repo.db.WithContext(ctx).Scopes(scope...).CreateInBatches(segments, 100).Error
+}
+
+func (repo *StorageRepository) ListBackfillSegment(ctx context.Context, filter model.BackfillSegment, scope ...func(*gorm.DB) *gorm.DB) ([]model.BackfillSegment, error) {
+	segments := []model.BackfillSegment{}
+	tx := repo.db.WithContext(ctx).Scopes(scope...).Model(model.BackfillSegment{}).Where(filter).Find(&segments)
+	return segments, tx.Error
+}
+
+func (repo *StorageRepository) UpdateBackfillSegment(ctx context.Context, filter model.BackfillSegment, segment *model.BackfillSegment, scope ...func(*gorm.DB) *gorm.DB) error {
+	return repo.db.WithContext(ctx).Scopes(scope...).Model(model.BackfillSegment{}).Where(filter).Updates(segment).Error
+}
+
 func (repo *StorageRepository) GetTransaction(ctx context.Context, filter model.Transaction, scope ...func(*gorm.DB) *gorm.DB) (model.Transaction, error) {
 	transaction := model.Transaction{}
 	tx := repo.db.WithContext(ctx).Scopes(scope...).Where(filter).First(&transaction)
@@ -92,6 +192,18 @@ func (repo *StorageRepository) GetTransaction(ctx context.Context, filter model.
 	return transaction, err
 }
 
+func (repo *StorageRepository) ListTransactionByAddress(ctx context.Context, filter model.AddressFilter, scope ...func(*gorm.DB) *gorm.DB) ([]model.Transaction, error) {
+	transactions := []model.Transaction{}
+	tx := repo.db.WithContext(ctx).Scopes(scope...).Model(model.Transaction{}).Scopes(filter.Where).Find(&transactions)
+	return transactions, tx.Error
+}
+
+func (repo *StorageRepository) ListLogs(ctx context.Context, filter model.LogFilter, scope ...func(*gorm.DB) *gorm.DB) ([]model.TransactionLog, error) {
+	logs := []model.TransactionLog{}
+	tx := repo.db.WithContext(ctx).Scopes(scope...).Model(model.TransactionLog{}).Scopes(filter.Where).Find(&logs)
+	return logs, tx.Error
+}
+
 func (repo *StorageRepository) Close() error {
 	db, err := repo.db.DB()
 	if err != nil {
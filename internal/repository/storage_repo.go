@@ -16,8 +16,18 @@ type StorageRepository interface {
 	UpdateCurrentBlockNumber(ctx context.Context, blockNumber *model.CurrentBlockNumber, scope ...func(*gorm.DB) *gorm.DB) error
 	GetBlock(ctx context.Context, filter model.Block, scope ...func(*gorm.DB) *gorm.DB) (model.Block, error)
 	ListBlock(ctx context.Context, filter model.Block, scope ...func(*gorm.DB) *gorm.DB) ([]model.Block, error)
+	ListBlockSummary(ctx context.Context, filter model.BlockRange, scope ...func(*gorm.DB) *gorm.DB) ([]model.BlockSummary, error)
 	CreateBlock(ctx context.Context, block *model.Block, scope ...func(*gorm.DB) *gorm.DB) error
 	UpdateBlock(ctx context.Context, filter model.Block, block *model.Block, scope ...func(*gorm.DB) *gorm.DB) error
+	OrphanBlocks(ctx context.Context, orphanedBlocks []*model.OrphanedBlock, scope ...func(*gorm.DB) *gorm.DB) error
+	ListOrphanedBlock(ctx context.Context, filter model.OrphanedBlock, scope ...func(*gorm.DB) *gorm.DB) ([]model.OrphanedBlock, error)
+	ListUncle(ctx context.Context, filter model.Uncle, scope ...func(*gorm.DB) *gorm.DB) ([]model.Uncle, error)
+	ListWithdrawal(ctx context.Context, filter model.Withdrawal, scope ...func(*gorm.DB) *gorm.DB) ([]model.Withdrawal, error)
+	CreateBackfillSegments(ctx context.Context, segments []*model.BackfillSegment, scope ...func(*gorm.DB) *gorm.DB) error
+	ListBackfillSegment(ctx context.Context, filter model.BackfillSegment, scope ...func(*gorm.DB) *gorm.DB) ([]model.BackfillSegment, error)
+	UpdateBackfillSegment(ctx context.Context, filter model.BackfillSegment, segment *model.BackfillSegment, scope ...func(*gorm.DB) *gorm.DB) error
 	GetTransaction(ctx context.Context, filter model.Transaction, scope ...func(*gorm.DB) *gorm.DB) (model.Transaction, error)
+	ListTransactionByAddress(ctx context.Context, filter model.AddressFilter, scope ...func(*gorm.DB) *gorm.DB) ([]model.Transaction, error)
+	ListLogs(ctx context.Context, filter model.LogFilter, scope ...func(*gorm.DB) *gorm.DB) ([]model.TransactionLog, error)
 	Close() error
 }
@@ -4,14 +4,19 @@ import (
 	"context"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
 type CrawlerService interface {
 	GetBlockNumber(ctx context.Context) (*big.Int, error)
+	GetFinality(ctx context.Context) (safe *big.Int, finalized *big.Int, err error)
 	GetBlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	GetBlockTransactionCount(ctx context.Context, number *big.Int) (uint, error)
 	GetTransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
 	GetTransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	GetBlockReceipts(ctx context.Context, block *types.Block) (types.Receipts, error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
 	Close()
 }
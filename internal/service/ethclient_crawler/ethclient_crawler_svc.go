@@ -2,66 +2,245 @@ package ethclient_crawler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/big"
 	"sync-ethereum/internal/config"
 	"sync-ethereum/internal/service"
+	"sync/atomic"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog"
 )
 
 var _ service.CrawlerService = (*EthClientCrawlerService)(nil)
 
-func NewEthClientCrawlerService(config config.Config) service.CrawlerService {
-	return &EthClientCrawlerService{
-		clientPool: _NewClientPool(config.EthClient.DialTimeout, config.EthClient.URL, config.EthClient.MaxClientConn),
+const _MethodNotFoundCode = -32601
+
+func NewEthClientCrawlerService(config config.Config, logger zerolog.Logger) (service.CrawlerService, error) {
+	receiptBatchSize := config.EthClient.ReceiptBatchSize
+	if receiptBatchSize <= 0 {
+		receiptBatchSize = 100 // default
+	}
+	provider, err := _NewProvider(config.EthClient, logger)
+	if err != nil {
+		return nil, err
 	}
+	return &EthClientCrawlerService{
+		provider:         provider,
+		receiptBatchSize: receiptBatchSize,
+		subscribeURL:     config.EthClient.SubscribeURL,
+		dialTimeout:      config.EthClient.DialTimeout,
+	}, nil
 }
 
 type EthClientCrawlerService struct {
-	clientPool *_ClientPool
+	provider         *_Provider
+	receiptBatchSize int
+	subscribeURL     string
+	dialTimeout      time.Duration
 }
 
 func (svc *EthClientCrawlerService) GetBlockNumber(ctx context.Context) (*big.Int, error) {
-	client, err := svc.clientPool.Get()
-	if err != nil {
-		return nil, err
-	}
-
-	number, err := client.BlockNumber(ctx)
+	var number uint64
+	err := svc.provider.Do(ctx, "eth_blockNumber", func(endpoint *_Endpoint, client *ethclient.Client) (err error) {
+		number, err = client.BlockNumber(ctx)
+		if err == nil {
+			endpoint._SetHead(number)
+		}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 	return big.NewInt(int64(number)), nil
 }
 
-func (svc *EthClientCrawlerService) GetBlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
-	client, err := svc.clientPool.Get()
+// GetFinality returns the heads of the safe and finalized tags
+func (svc *EthClientCrawlerService) GetFinality(ctx context.Context) (safe *big.Int, finalized *big.Int, err error) {
+	err = svc.provider.Do(ctx, "eth_getBlockByNumber", func(_ *_Endpoint, client *ethclient.Client) error {
+		header, err := client.HeaderByNumber(ctx, big.NewInt(rpc.SafeBlockNumber.Int64()))
+		if err != nil {
+			return err
+		}
+		safe = header.Number
+		return nil
+	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	err = svc.provider.Do(ctx, "eth_getBlockByNumber", func(_ *_Endpoint, client *ethclient.Client) error {
+		header, err := client.HeaderByNumber(ctx, big.NewInt(rpc.FinalizedBlockNumber.Int64()))
+		if err != nil {
+			return err
+		}
+		finalized = header.Number
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if safe.Cmp(finalized) < 0 { // the tags may be answered by different endpoints
+		safe = finalized
 	}
+	return safe, finalized, nil
+}
+
+func (svc *EthClientCrawlerService) GetBlockByNumber(ctx context.Context, number *big.Int) (block *types.Block, err error) {
+	err = svc.provider.Do(ctx, "eth_getBlockByNumber", func(_ *_Endpoint, client *ethclient.Client) (err error) {
+		block, err = client.BlockByNumber(ctx, number)
+		return err
+	})
+	return block, err
+}
 
-	return client.BlockByNumber(ctx, number)
+func (svc *EthClientCrawlerService) GetBlockTransactionCount(ctx context.Context, number *big.Int) (count uint, err error) {
+	err = svc.provider.Do(ctx, "eth_getBlockTransactionCountByNumber", func(_ *_Endpoint, client *ethclient.Client) error {
+		var result *hexutil.Uint
+		if err := client.Client().CallContext(ctx, &result, "eth_getBlockTransactionCountByNumber", hexutil.EncodeBig(number)); err != nil {
+			return err
+		}
+		if result == nil {
+			return ethereum.NotFound
+		}
+		count = uint(*result)
+		return nil
+	})
+	return count, err
 }
 
 func (svc *EthClientCrawlerService) GetTransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error) {
-	client, err := svc.clientPool.Get()
+	err = svc.provider.Do(ctx, "eth_getTransactionByHash", func(_ *_Endpoint, client *ethclient.Client) (err error) {
+		tx, isPending, err = client.TransactionByHash(ctx, hash)
+		return err
+	})
+	return tx, isPending, err
+}
+
+func (svc *EthClientCrawlerService) GetTransactionReceipt(ctx context.Context, txHash common.Hash) (receipt *types.Receipt, err error) {
+	err = svc.provider.Do(ctx, "eth_getTransactionReceipt", func(_ *_Endpoint, client *ethclient.Client) (err error) {
+		receipt, err = client.TransactionReceipt(ctx, txHash)
+		return err
+	})
+	return receipt, err
+}
+
+// GetBlockReceipts returns the receipts of the block in transaction order,
+// it prefers eth_getBlockReceipts and falls back to batched eth_getTransactionReceipt when the node does not support it
+func (svc *EthClientCrawlerService) GetBlockReceipts(ctx context.Context, block *types.Block) (receipts types.Receipts, err error) {
+	if block.Transactions().Len() == 0 {
+		return types.Receipts{}, nil
+	}
+
+	// the fallback charges the quota of each batch itself
+	cost := svc.provider.config.GetMethodCost("eth_getBlockReceipts")
+	costOf := func(endpoint *_Endpoint) int {
+		if atomic.LoadInt32(&endpoint.blockReceiptsUnsupported) == 1 {
+			return 0
+		}
+		return cost
+	}
+	err = svc.provider._Do(ctx, "eth_getBlockReceipts", costOf, func(endpoint *_Endpoint, client *ethclient.Client) (err error) {
+		if atomic.LoadInt32(&endpoint.blockReceiptsUnsupported) == 0 {
+			receipts, err = client.BlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(block.Hash(), false))
+			var rpcErr rpc.Error
+			if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == _MethodNotFoundCode {
+				atomic.StoreInt32(&endpoint.blockReceiptsUnsupported, 1)
+			} else if err != nil {
+				return err
+			}
+		}
+		if atomic.LoadInt32(&endpoint.blockReceiptsUnsupported) == 1 {
+			receipts, err = svc._BatchTransactionReceipts(ctx, endpoint, client.Client(), block.Transactions())
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(receipts) != block.Transactions().Len() {
+			return fmt.Errorf("receipt count mismatch, block_hash: %s, expected: %d, got: %d", block.Hash().Hex(), block.Transactions().Len(), len(receipts))
+		}
+		for idx, tx := range block.Transactions() {
+			if receipts[idx].TxHash != tx.Hash() {
+				return fmt.Errorf("receipt order mismatch, block_hash: %s, tx_index: %d", block.Hash().Hex(), idx)
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
+	return receipts, nil
+}
 
-	return client.TransactionByHash(ctx, hash)
+func (svc *EthClientCrawlerService) _BatchTransactionReceipts(ctx context.Context, endpoint *_Endpoint, client *rpc.Client, txs types.Transactions) (types.Receipts, error) {
+	receipts := make(types.Receipts, len(txs))
+	for start := 0; start < len(txs); start += svc.receiptBatchSize {
+		end := start + svc.receiptBatchSize
+		if end > len(txs) {
+			end = len(txs)
+		}
+		batch := make([]rpc.BatchElem, 0, end-start)
+		for idx := start; idx < end; idx++ {
+			batch = append(batch, rpc.BatchElem{
+				Method: "eth_getTransactionReceipt",
+				Args:   []interface{}{txs[idx].Hash()},
+				Result: &receipts[idx],
+			})
+		}
+		if err := svc.provider._Acquire(ctx, endpoint, svc.provider.config.GetMethodCost("eth_getTransactionReceipt")*len(batch)); err != nil {
+			return nil, err
+		}
+		if err := client.BatchCallContext(ctx, batch); err != nil {
+			return nil, err
+		}
+		for idx, elem := range batch {
+			if elem.Error != nil {
+				return nil, elem.Error
+			}
+			if receipts[start+idx] == nil {
+				return nil, fmt.Errorf("%w, tx_hash: %s", ethereum.NotFound, txs[start+idx].Hash().Hex())
+			}
+		}
+	}
+	return receipts, nil
 }
 
-func (svc *EthClientCrawlerService) GetTransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
-	client, err := svc.clientPool.Get()
+// SubscribeNewHead subscribes newHeads over the dedicated ws or ipc connection, which is closed on unsubscribe
+func (svc *EthClientCrawlerService) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	if len(svc.subscribeURL) == 0 {
+		return nil, errors.New("subscribe url is not configured")
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, svc.dialTimeout)
+	defer cancel()
+	client, err := ethclient.DialContext(dialCtx, svc.subscribeURL)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := client.SubscribeNewHead(ctx, ch)
 	if err != nil {
+		client.Close()
 		return nil, err
 	}
+	return &_HeadSubscription{Subscription: sub, client: client}, nil
+}
+
+type _HeadSubscription struct {
+	ethereum.Subscription
+	client *ethclient.Client
+}
 
-	return client.TransactionReceipt(ctx, txHash)
+func (sub *_HeadSubscription) Unsubscribe() {
+	sub.Subscription.Unsubscribe()
+	sub.client.Close()
 }
 
 func (svc *EthClientCrawlerService) Close() {
-	svc.clientPool.Close()
+	svc.provider.Close()
 }
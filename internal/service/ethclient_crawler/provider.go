@@ -0,0 +1,442 @@
+package ethclient_crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync-ethereum/internal/config"
+	pkgErrors "sync-ethereum/internal/errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// weight of the latest sample in the moving averages
+	_MovingAverageFactor = 0.2
+	// json-rpc error code of the providers when the request exceeds the quota
+	_LimitExceededCode = -32005
+	// the backoff of a rate limited endpoint doubles up to 2^_MaxThrottleShift times
+	_MaxThrottleShift = 5
+)
+
+func _NewEndpoint(dialTimeout time.Duration, endpoint config.EthClientEndpointConfig, maxClientConn int) *_Endpoint {
+	weight := endpoint.Weight
+	if weight <= 0 {
+		weight = 1 // default
+	}
+	var limiter *rate.Limiter // unlimited
+	if endpoint.RateLimit > 0 {
+		burst := endpoint.Burst
+		if burst <= 0 {
+			burst = int(math.Ceil(endpoint.RateLimit))
+		}
+		limiter = rate.NewLimiter(rate.Limit(endpoint.RateLimit), burst)
+	}
+	return &_Endpoint{
+		url:     endpoint.URL,
+		weight:  weight,
+		pool:    _NewClientPool(dialTimeout, endpoint.URL, maxClientConn),
+		limiter: limiter,
+		healthy: true,
+	}
+}
+
+type _Endpoint struct {
+	url     string
+	weight  int
+	pool    *_ClientPool
+	limiter *rate.Limiter
+
+	throttleLock   sync.Mutex
+	throttledUntil time.Time
+	throttleCount  int
+
+	lock      sync.RWMutex
+	latency   time.Duration
+	errorRate float64
+	head      uint64
+	healthy   bool
+
+	// set once the node answers eth_getBlockReceipts with method not found
+	blockReceiptsUnsupported int32
+}
+
+// _Record updates the moving averages, the endpoint is ejected once its error rate exceeds maxErrorRate,
+// and it is not routed until the next health check passes
+func (e *_Endpoint) _Record(latency time.Duration, failed bool, maxErrorRate float64) (ejected bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	sample := 0.0
+	if failed {
+		sample = 1
+	}
+	e.errorRate = e.errorRate*(1-_MovingAverageFactor) + sample*_MovingAverageFactor
+	if !failed {
+		e.latency = time.Duration(float64(e.latency)*(1-_MovingAverageFactor) + float64(latency)*_MovingAverageFactor)
+	}
+	if e.healthy && e.errorRate > maxErrorRate {
+		e.healthy = false
+		return true
+	}
+	return false
+}
+
+func (e *_Endpoint) _SetHead(head uint64) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if head > e.head {
+		e.head = head
+	}
+}
+
+// _Throttle backs off the endpoint which answers the quota is exceeded
+func (e *_Endpoint) _Throttle(now time.Time, backoff time.Duration) time.Duration {
+	e.throttleLock.Lock()
+	defer e.throttleLock.Unlock()
+	shift := e.throttleCount
+	if shift > _MaxThrottleShift {
+		shift = _MaxThrottleShift
+	}
+	e.throttleCount++
+	backoff = backoff << shift
+	e.throttledUntil = now.Add(backoff)
+	return backoff
+}
+
+func (e *_Endpoint) _ResetThrottle() {
+	e.throttleLock.Lock()
+	defer e.throttleLock.Unlock()
+	e.throttleCount = 0
+}
+
+func (e *_Endpoint) _Cooldown(now time.Time) time.Duration {
+	e.throttleLock.Lock()
+	defer e.throttleLock.Unlock()
+	return e.throttledUntil.Sub(now)
+}
+
+// _Available reports whether the endpoint can serve the cost without waiting
+func (e *_Endpoint) _Available(now time.Time, cost int) bool {
+	if e._Cooldown(now) > 0 {
+		return false
+	}
+	if e.limiter == nil || cost <= 0 {
+		return true
+	}
+	if cost > e.limiter.Burst() {
+		cost = e.limiter.Burst()
+	}
+	return e.limiter.TokensAt(now) >= float64(cost)
+}
+
+// _Score is the routing weight, an unhealthy endpoint has no score
+func (e *_Endpoint) _Score() float64 {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	if !e.healthy {
+		return 0
+	}
+	return float64(e.weight) * (1 - e.errorRate)
+}
+
+func _NewProvider(config config.EthClientConfig, logger zerolog.Logger) (*_Provider, error) {
+	endpoints := []*_Endpoint{}
+	for _, endpoint := range config.GetEndpoints() {
+		endpoints = append(endpoints, _NewEndpoint(config.DialTimeout, endpoint, config.MaxClientConn))
+	}
+	if len(endpoints) == 0 {
+		return nil, errors.New("no eth client endpoint, set eth_client.url or eth_client.endpoints")
+	}
+	maxRetry := config.MaxRetry
+	if maxRetry < 0 {
+		maxRetry = 0
+	}
+	if maxRetry > len(endpoints)-1 {
+		maxRetry = len(endpoints) - 1
+	}
+	interval := config.HealthCheckInterval
+	if interval <= 0 {
+		interval = 10 * time.Second // default
+	}
+	backoff := config.RateLimitBackoff
+	if backoff <= 0 {
+		backoff = time.Second // default
+	}
+	provider := &_Provider{
+		config:       config,
+		logger:       logger,
+		endpoints:    endpoints,
+		dialTimeout:  config.DialTimeout,
+		maxRetry:     maxRetry,
+		interval:     interval,
+		maxBlockLag:  config.MaxBlockLag,
+		maxErrorRate: config.MaxErrorRate,
+		backoff:      backoff,
+		closed:       make(chan struct{}),
+	}
+	provider.wg.Add(1)
+	go provider._Run()
+	return provider, nil
+}
+
+// _Provider routes calls to the healthy endpoints by weight, and retries failed calls on another endpoint
+type _Provider struct {
+	config       config.EthClientConfig
+	logger       zerolog.Logger
+	endpoints    []*_Endpoint
+	dialTimeout  time.Duration
+	maxRetry     int
+	interval     time.Duration
+	maxBlockLag  uint64
+	maxErrorRate float64
+	backoff      time.Duration
+	closed       chan struct{}
+	closeOnce    sync.Once
+	wg           sync.WaitGroup
+}
+
+// Do calls fn with a picked endpoint once the endpoint has quota for the method, and picks another endpoint when fn fails,
+// it returns ErrRateLimited when every tried endpoint is out of quota
+func (p *_Provider) Do(ctx context.Context, method string, fn func(endpoint *_Endpoint, client *ethclient.Client) error) error {
+	cost := p.config.GetMethodCost(method)
+	return p._Do(ctx, method, func(*_Endpoint) int { return cost }, fn)
+}
+
+// _Do is Do with the quota cost charged by the picked endpoint, the call which charges its own quota costs 0
+func (p *_Provider) _Do(ctx context.Context, method string, costOf func(endpoint *_Endpoint) int, fn func(endpoint *_Endpoint, client *ethclient.Client) error) error {
+	var err error
+	tried := map[*_Endpoint]bool{}
+	for attempt := 0; attempt <= p.maxRetry; attempt++ {
+		endpoint := p._Pick(tried, costOf)
+		tried[endpoint] = true
+
+		if err = p._Acquire(ctx, endpoint, costOf(endpoint)); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			p.logger.Warn().Err(err).Str("endpoint", endpoint.url).Str("method", method).Int("attempt", attempt).Msg("endpoint quota exhausted")
+			continue
+		}
+		var client *ethclient.Client
+		client, err = endpoint.pool.Get()
+		if err != nil {
+			p._Record(endpoint, 0, true)
+			p.logger.Warn().Err(err).Str("endpoint", endpoint.url).Int("attempt", attempt).Msg("dial endpoint error")
+			continue
+		}
+		start := time.Now()
+		err = fn(endpoint, client)
+		if _IsRateLimited(err) {
+			backoff := endpoint._Throttle(time.Now(), p.backoff)
+			p.logger.Warn().Err(err).Str("endpoint", endpoint.url).Str("method", method).Dur("backoff", backoff).Msg("endpoint rate limited")
+			err = fmt.Errorf("%w: %s", pkgErrors.ErrRateLimited, err.Error())
+			continue
+		}
+		p._Record(endpoint, time.Since(start), _IsEndpointFailure(ctx, err))
+		if err == nil {
+			endpoint._ResetThrottle()
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		p.logger.Warn().Err(err).Str("endpoint", endpoint.url).Int("attempt", attempt).Msg("call endpoint error")
+	}
+	return err
+}
+
+// _Acquire waits until the endpoint has quota for the cost, and gives up with ErrRateLimited when the wait outlives ctx
+func (p *_Provider) _Acquire(ctx context.Context, endpoint *_Endpoint, cost int) error {
+	now := time.Now()
+	wait := endpoint._Cooldown(now)
+	var reservation *rate.Reservation
+	if endpoint.limiter != nil && cost > 0 {
+		if cost > endpoint.limiter.Burst() {
+			cost = endpoint.limiter.Burst()
+		}
+		reservation = endpoint.limiter.ReserveN(now, cost)
+		if delay := reservation.DelayFrom(now); delay > wait {
+			wait = delay
+		}
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && now.Add(wait).After(deadline) {
+		if reservation != nil {
+			reservation.CancelAt(now)
+		}
+		return pkgErrors.ErrRateLimited
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		if reservation != nil {
+			reservation.Cancel()
+		}
+		return ctx.Err()
+	}
+}
+
+func (p *_Provider) _Record(endpoint *_Endpoint, latency time.Duration, failed bool) {
+	if endpoint._Record(latency, failed, p.maxErrorRate) {
+		p.logger.Warn().Str("endpoint", endpoint.url).Bool("healthy", false).Float64("max_error_rate", p.maxErrorRate).Msg("endpoint ejected by error rate")
+	}
+}
+
+// _Pick chooses an untried endpoint by score, the healthy endpoints with quota for the cost are preferred,
+// and it falls back to the ejected endpoints when no healthy one is left
+func (p *_Provider) _Pick(tried map[*_Endpoint]bool, costOf func(endpoint *_Endpoint) int) *_Endpoint {
+	candidates := []*_Endpoint{}
+	scores := []float64{}
+	total := 0.0
+	now := time.Now()
+	for _, available := range []bool{true, false} {
+		for _, endpoint := range p.endpoints {
+			if tried[endpoint] || (available && !endpoint._Available(now, costOf(endpoint))) {
+				continue
+			}
+			if score := endpoint._Score(); score > 0 {
+				candidates = append(candidates, endpoint)
+				scores = append(scores, score)
+				total += score
+			}
+		}
+		if len(candidates) > 0 {
+			break
+		}
+	}
+	if len(candidates) == 0 {
+		for _, endpoint := range p.endpoints {
+			if !tried[endpoint] {
+				return endpoint
+			}
+		}
+		return p.endpoints[rand.Intn(len(p.endpoints))]
+	}
+
+	r := rand.Float64() * total
+	for i, score := range scores {
+		if r < score {
+			return candidates[i]
+		}
+		r -= score
+	}
+	return candidates[len(candidates)-1]
+}
+
+func (p *_Provider) _Run() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		p._HealthCheck()
+		select {
+		case <-p.closed:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// _HealthCheck probes the head of every endpoint, then ejects the failing, erroring or lagging ones
+func (p *_Provider) _HealthCheck() {
+	alive := make([]bool, len(p.endpoints))
+	wg := sync.WaitGroup{}
+	for i, endpoint := range p.endpoints {
+		wg.Add(1)
+		go func(i int, endpoint *_Endpoint) {
+			defer wg.Done()
+			client, err := endpoint.pool.Get()
+			if err != nil {
+				p._Record(endpoint, 0, true)
+				p.logger.Warn().Err(err).Str("endpoint", endpoint.url).Msg("health check dial endpoint error")
+				return
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), p.dialTimeout)
+			defer cancel()
+			start := time.Now()
+			head, err := client.BlockNumber(ctx)
+			p._Record(endpoint, time.Since(start), err != nil)
+			if err != nil {
+				p.logger.Warn().Err(err).Str("endpoint", endpoint.url).Msg("health check endpoint error")
+				return
+			}
+			endpoint._SetHead(head)
+			alive[i] = true
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	var maxHead uint64
+	for _, endpoint := range p.endpoints {
+		endpoint.lock.RLock()
+		if endpoint.head > maxHead {
+			maxHead = endpoint.head
+		}
+		endpoint.lock.RUnlock()
+	}
+	for i, endpoint := range p.endpoints {
+		endpoint.lock.Lock()
+		lag := maxHead - endpoint.head
+		healthy := alive[i] && endpoint.errorRate <= p.maxErrorRate && lag <= p.maxBlockLag
+		if healthy != endpoint.healthy {
+			event := p.logger.Info()
+			if !healthy {
+				event = p.logger.Warn()
+			}
+			event.Str("endpoint", endpoint.url).Bool("healthy", healthy).Uint64("lag", lag).Float64("error_rate", endpoint.errorRate).Msg("endpoint health changed")
+		}
+		endpoint.healthy = healthy
+		p.logger.Debug().Str("endpoint", endpoint.url).Bool("healthy", healthy).Uint64("head", endpoint.head).Dur("latency", endpoint.latency).Float64("error_rate", endpoint.errorRate).Msg("endpoint health")
+		endpoint.lock.Unlock()
+	}
+}
+
+func (p *_Provider) Close() {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+	})
+	p.wg.Wait()
+	for _, endpoint := range p.endpoints {
+		endpoint.pool.Close()
+	}
+}
+
+// _IsRateLimited reports whether the endpoint answers the quota is exceeded
+func _IsRateLimited(err error) bool {
+	if err == nil {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == _LimitExceededCode
+}
+
+// _IsEndpointFailure reports whether the error is caused by the endpoint rather than the request
+func _IsEndpointFailure(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, ethereum.NotFound) || errors.Is(err, pkgErrors.ErrRateLimited) {
+		return false
+	}
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
@@ -0,0 +1,15 @@
+package ethclient_crawler
+
+import (
+	"sync-ethereum/internal/config"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestNewProviderWithoutEndpoint(t *testing.T) {
+	provider, err := _NewProvider(config.EthClientConfig{}, zerolog.Nop())
+	if err == nil || provider != nil {
+		t.Fatalf("got %v %v, want an error for no endpoint", provider, err)
+	}
+}
@@ -2,16 +2,30 @@ package service
 
 import (
 	"context"
+	"math/big"
 	"sync-ethereum/internal/model"
 )
 
 type StorageService interface {
 	GetCurrentBlockNumber(ctx context.Context) (model.GormBigInt, error)
 	UpdateCurrentBlockNumber(ctx context.Context, blockNumber model.GormBigInt, onlineBlockNumber model.GormBigInt) error
+	GetFinality(ctx context.Context) (safe model.GormBigInt, finalized model.GormBigInt, err error)
+	UpdateFinality(ctx context.Context, safe model.GormBigInt, finalized model.GormBigInt) error
 	GetBlock(ctx context.Context, filter model.Block) (model.Block, error)
+	GetBlockHeader(ctx context.Context, blockNumber model.GormBigInt) (model.Block, error)
 	ListBlock(ctx context.Context, filter model.Block, pagination model.Pagination, sorting model.Sorting) ([]model.Block, error)
+	ListBlockSummary(ctx context.Context, blockRange model.BlockRange) ([]model.BlockSummary, error)
 	CreateBlock(ctx context.Context, block *model.Block) error
 	UpdateBlock(ctx context.Context, filter model.Block, block *model.Block) error
+	OrphanBlocks(ctx context.Context, orphanedBlocks ...*model.OrphanedBlock) error
+	ListOrphanedBlock(ctx context.Context, filter model.OrphanedBlock, pagination model.Pagination, sorting model.Sorting) ([]model.OrphanedBlock, error)
+	ListUncle(ctx context.Context, blockNumber model.GormBigInt) ([]model.Uncle, error)
+	ListBlockWithdrawal(ctx context.Context, blockNumber model.GormBigInt) ([]model.Withdrawal, error)
+	ListAddressWithdrawal(ctx context.Context, address string, blockRange model.BlockRange, pagination model.Pagination) ([]model.Withdrawal, error)
+	PlanBackfill(ctx context.Context, from, to *big.Int, segmentSize int64) ([]model.BackfillSegment, error)
+	UpdateBackfillSegment(ctx context.Context, segment *model.BackfillSegment) error
 	GetTransaction(ctx context.Context, filter model.Transaction) (model.Transaction, error)
+	ListTransactionByAddress(ctx context.Context, filter model.AddressFilter, limit int) ([]model.Transaction, error)
+	ListLogs(ctx context.Context, filter model.LogFilter, pagination model.Pagination) ([]model.TransactionLog, error)
 	Close() error
 }
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"math/big"
 	"sync-ethereum/internal/model"
 	"sync-ethereum/internal/repository"
 	"sync-ethereum/internal/service"
@@ -9,6 +10,9 @@ import (
 
 var _ service.StorageService = (*StorageService)(nil)
 
+// segments of each query, the two placeholders of a segment stay below the placeholder limit of the drivers
+const _BackfillSegmentBatchSize = 100
+
 func NewStorageService(repo repository.StorageRepository) service.StorageService {
 	return &StorageService{
 		repo: repo,
@@ -31,14 +35,37 @@ func (svc *StorageService) UpdateCurrentBlockNumber(ctx context.Context, blockNu
 	return svc.repo.UpdateCurrentBlockNumber(ctx, &model.CurrentBlockNumber{BlockNumber: blockNumber, OnlineBlockNumber: onlineBlockNumber})
 }
 
+// GetFinality returns the latest recorded safe and finalized heads
+func (svc *StorageService) GetFinality(ctx context.Context) (safe model.GormBigInt, finalized model.GormBigInt, err error) {
+	currentBlockNumber, err := svc.repo.GetCurrentBlockNumber(ctx)
+	if err != nil {
+		return model.GormBigInt{}, model.GormBigInt{}, err
+	}
+	return currentBlockNumber.SafeBlockNumber, currentBlockNumber.FinalizedBlockNumber, nil
+}
+
+func (svc *StorageService) UpdateFinality(ctx context.Context, safe model.GormBigInt, finalized model.GormBigInt) error {
+	return svc.repo.UpdateCurrentBlockNumber(ctx, &model.CurrentBlockNumber{SafeBlockNumber: safe, FinalizedBlockNumber: finalized})
+}
+
 func (svc *StorageService) GetBlock(ctx context.Context, filter model.Block) (model.Block, error) {
 	return svc.repo.GetBlock(ctx, filter, model.Block{}.Preload)
 }
 
+// GetBlockHeader returns the block without its transactions, uncles and withdrawals
+func (svc *StorageService) GetBlockHeader(ctx context.Context, blockNumber model.GormBigInt) (model.Block, error) {
+	return svc.repo.GetBlock(ctx, model.Block{}, model.BlockNumber(blockNumber))
+}
+
 func (svc *StorageService) ListBlock(ctx context.Context, filter model.Block, pagination model.Pagination, sorting model.Sorting) ([]model.Block, error) {
 	return svc.repo.ListBlock(ctx, filter, pagination.LimitAndOffset, sorting.Sort, model.Block{}.Preload)
 }
 
+// ListBlockSummary returns the stored blocks of the range in ascending order with their stored transaction count
+func (svc *StorageService) ListBlockSummary(ctx context.Context, blockRange model.BlockRange) ([]model.BlockSummary, error) {
+	return svc.repo.ListBlockSummary(ctx, blockRange, model.BlockNumberSorting(model.SortASC).Sort)
+}
+
 func (svc *StorageService) CreateBlock(ctx context.Context, block *model.Block) error {
 	return svc.repo.CreateBlock(ctx, block, model.Block{}.OnConflict)
 }
@@ -47,10 +74,66 @@ func (svc *StorageService) UpdateBlock(ctx context.Context, filter model.Block,
 	return svc.repo.UpdateBlock(ctx, filter, block)
 }
 
+func (svc *StorageService) OrphanBlocks(ctx context.Context, orphanedBlocks ...*model.OrphanedBlock) error {
+	if len(orphanedBlocks) == 0 {
+		return nil
+	}
+	return svc.repo.OrphanBlocks(ctx, orphanedBlocks)
+}
+
+func (svc *StorageService) ListOrphanedBlock(ctx context.Context, filter model.OrphanedBlock, pagination model.Pagination, sorting model.Sorting) ([]model.OrphanedBlock, error) {
+	return svc.repo.ListOrphanedBlock(ctx, filter, pagination.LimitAndOffset, sorting.Sort)
+}
+
+func (svc *StorageService) ListUncle(ctx context.Context, blockNumber model.GormBigInt) ([]model.Uncle, error) {
+	return svc.repo.ListUncle(ctx, model.Uncle{}, model.BlockNumber(blockNumber), model.Uncle{}.Sort)
+}
+
+func (svc *StorageService) ListBlockWithdrawal(ctx context.Context, blockNumber model.GormBigInt) ([]model.Withdrawal, error) {
+	return svc.repo.ListWithdrawal(ctx, model.Withdrawal{}, model.BlockNumber(blockNumber), model.Withdrawal{}.Sort)
+}
+
+func (svc *StorageService) ListAddressWithdrawal(ctx context.Context, address string, blockRange model.BlockRange, pagination model.Pagination) ([]model.Withdrawal, error) {
+	return svc.repo.ListWithdrawal(ctx, model.Withdrawal{Address: address}, blockRange.Where, pagination.LimitAndOffset, model.Withdrawal{}.Sort)
+}
+
+// PlanBackfill creates the missing segments of the range, and returns all of them with their progress
+func (svc *StorageService) PlanBackfill(ctx context.Context, from, to *big.Int, segmentSize int64) ([]model.BackfillSegment, error) {
+	planned := model.PlanBackfillSegments(from, to, segmentSize)
+	if err := svc.repo.CreateBackfillSegments(ctx, planned); err != nil {
+		return nil, err
+	}
+	segments := []model.BackfillSegment{}
+	for start := 0; start < len(planned); start += _BackfillSegmentBatchSize {
+		end := start + _BackfillSegmentBatchSize
+		if end > len(planned) {
+			end = len(planned)
+		}
+		batch, err := svc.repo.ListBackfillSegment(ctx, model.BackfillSegment{}, model.BackfillSegmentIn(planned[start:end]), model.BackfillSegment{}.Sort)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, batch...)
+	}
+	return segments, nil
+}
+
+func (svc *StorageService) UpdateBackfillSegment(ctx context.Context, segment *model.BackfillSegment) error {
+	return svc.repo.UpdateBackfillSegment(ctx, model.BackfillSegment{ID: segment.ID}, segment)
+}
+
 func (svc *StorageService) GetTransaction(ctx context.Context, filter model.Transaction) (model.Transaction, error) {
 	return svc.repo.GetTransaction(ctx, filter, model.Transaction{}.Preload)
 }
 
+func (svc *StorageService) ListTransactionByAddress(ctx context.Context, filter model.AddressFilter, limit int) ([]model.Transaction, error) {
+	return svc.repo.ListTransactionByAddress(ctx, filter, model.Pagination{PerPage: int64(limit)}.LimitAndOffset, model.Transaction{}.Sort)
+}
+
+func (svc *StorageService) ListLogs(ctx context.Context, filter model.LogFilter, pagination model.Pagination) ([]model.TransactionLog, error) {
+	return svc.repo.ListLogs(ctx, filter, pagination.LimitAndOffset, model.TransactionLog{}.Sort)
+}
+
 func (svc *StorageService) Close() error {
 	return svc.repo.Close()
 }
@@ -0,0 +1,40 @@
+package wireset
+
+import (
+	"strings"
+	"sync-ethereum/internal/config"
+	"sync-ethereum/pkg/blob"
+	"sync-ethereum/pkg/blob/filesystem"
+	"sync-ethereum/pkg/blob/s3"
+
+	"github.com/pkg/errors"
+)
+
+// InitBlobStore returns nil when no driver is configured
+func InitBlobStore(config config.Config) (store blob.Store, err error) {
+	switch strings.ToLower(config.Blob.Driver) {
+	case "":
+		return nil, nil
+	case "filesystem":
+		store, err = filesystem.NewFilesystemStore(filesystem.FilesystemOption{
+			Dir: config.Blob.FilesystemOption.Dir,
+		})
+	case "s3":
+		store, err = s3.NewS3Store(s3.S3Option{
+			Endpoint:        config.Blob.S3Option.Endpoint,
+			Region:          config.Blob.S3Option.Region,
+			Bucket:          config.Blob.S3Option.Bucket,
+			AccessKeyID:     config.Blob.S3Option.AccessKeyID,
+			SecretAccessKey: config.Blob.S3Option.SecretAccessKey,
+			Prefix:          config.Blob.S3Option.Prefix,
+			PathStyle:       config.Blob.S3Option.PathStyle,
+			Timeout:         config.Blob.S3Option.Timeout,
+		})
+	default:
+		err = errors.New("no supported blob driver [" + config.Blob.Driver + "]")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
@@ -3,9 +3,13 @@ package wireset
 import (
 	"strings"
 	"sync-ethereum/internal/config"
+	"sync-ethereum/internal/model"
 	"sync-ethereum/pkg/mq"
 	"sync-ethereum/pkg/mq/confluentkafka"
 	"sync-ethereum/pkg/mq/kafka"
+	"sync-ethereum/pkg/mq/memory"
+	"sync-ethereum/pkg/mq/nats"
+	"sync-ethereum/pkg/mq/redisstream"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
@@ -13,6 +17,42 @@ import (
 
 func InitMQ(config config.Config, log zerolog.Logger) (queue mq.MQ, err error) {
 	switch strings.ToLower(config.MQ.Driver) {
+	case "memory":
+		queue = memory.NewMemoryMQ(memory.MemoryOption{
+			RedeliveryDelay: config.MQ.MemoryOption.RedeliveryDelay,
+		})
+	case "redis":
+		queue, err = redisstream.NewRedisStreamMQ(redisstream.RedisStreamOption{
+			Addr:            config.MQ.RedisOption.Addr,
+			Username:        config.MQ.RedisOption.Username,
+			Password:        config.MQ.RedisOption.Password,
+			DB:              config.MQ.RedisOption.DB,
+			ConsumerGroup:   config.MQ.RedisOption.ConsumerGroup,
+			Consumer:        config.MQ.RedisOption.Consumer,
+			MaxLen:          config.MQ.RedisOption.MaxLen,
+			BatchSize:       config.MQ.RedisOption.BatchSize,
+			BlockTimeout:    config.MQ.RedisOption.BlockTimeout,
+			ClaimMinIdle:    config.MQ.RedisOption.ClaimMinIdle,
+			ClaimInterval:   config.MQ.RedisOption.ClaimInterval,
+			RedeliveryDelay: config.MQ.RedisOption.RedeliveryDelay,
+		}, log)
+	case "nats":
+		queue, err = nats.NewNatsMQ(nats.NatsOption{
+			URL:           config.MQ.NatsOption.URL,
+			Username:      config.MQ.NatsOption.Username,
+			Password:      config.MQ.NatsOption.Password,
+			Token:         config.MQ.NatsOption.Token,
+			StreamPrefix:  config.MQ.NatsOption.StreamPrefix,
+			Storage:       config.MQ.NatsOption.Storage,
+			Replicas:      config.MQ.NatsOption.Replicas,
+			MaxAge:        config.MQ.NatsOption.MaxAge,
+			Durable:       config.MQ.NatsOption.Durable,
+			MaxDeliver:    config.MQ.NatsOption.MaxDeliver,
+			AckWait:       config.MQ.NatsOption.AckWait,
+			MaxAckPending: config.MQ.NatsOption.MaxAckPending,
+			FetchBatch:    config.MQ.NatsOption.FetchBatch,
+			FetchTimeout:  config.MQ.NatsOption.FetchTimeout,
+		}, log)
 	case "kafka":
 		queue, err = kafka.NewKafkaMQ(kafka.KafkaOption{
 			Brokers:        config.MQ.KafkaOption.Brokers,
@@ -20,6 +60,7 @@ func InitMQ(config config.Config, log zerolog.Logger) (queue mq.MQ, err error) {
 			OffsetsInitial: config.MQ.KafkaOption.OffsetsInitial,
 			FetchDefault:   config.MQ.KafkaOption.FetchDefault,
 			RequiredAcks:   config.MQ.KafkaOption.RequiredAcks,
+			PartitionByKey: config.MQ.KafkaOption.PartitionByKey,
 			LoggerAdapter:  mq.WrapWatermillLogger(log),
 		})
 	case "confluentkafka":
@@ -40,9 +81,13 @@ func InitMQ(config config.Config, log zerolog.Logger) (queue mq.MQ, err error) {
 			Retries:                config.MQ.ConfluentKafkaOption.Retries,
 			BatchSize:              config.MQ.ConfluentKafkaOption.BatchSize,
 			FlushWaitMs:            config.MQ.ConfluentKafkaOption.FlushWaitMs,
+			RawFormat:              config.MQ.ConfluentKafkaOption.RawFormat,
 			FetchMaxBytes:          config.MQ.ConfluentKafkaOption.FetchMaxBytes,
 			MaxPartitionFetchBytes: config.MQ.ConfluentKafkaOption.MaxPartitionFetchBytes,
 			PollTimeoutMs:          config.MQ.ConfluentKafkaOption.PollTimeoutMs,
+			RedeliveryDelayMs:      config.MQ.ConfluentKafkaOption.RedeliveryDelayMs,
+			MaxRedeliveries:        config.MQ.ConfluentKafkaOption.MaxRedeliveries,
+			ReleaseDelayMs:         config.MQ.ConfluentKafkaOption.ReleaseDelayMs,
 			SASlUserName:           config.MQ.ConfluentKafkaOption.SASlUserName,
 			SASLPassword:           config.MQ.ConfluentKafkaOption.SASLPassword,
 			SASLMechanisms:         config.MQ.ConfluentKafkaOption.SASLMechanisms,
@@ -52,8 +97,15 @@ func InitMQ(config config.Config, log zerolog.Logger) (queue mq.MQ, err error) {
 		err = errors.New("no supported driver [" + config.MQ.Driver + "]")
 	}
 	if queue != nil && err == nil {
+		queue = mq.WithRetry(queue, mq.RetryOption{
+			MaxAttempts:     config.MQ.Retry.MaxAttempts,
+			Backoff:         config.MQ.Retry.Backoff,
+			MaxBackoff:      config.MQ.Retry.MaxBackoff,
+			DeadLetterTopic: config.MQ.DeadLetterTopic,
+		}, log)
 		queue.SubscriberMiddleware(func(key string, data []byte) {
-			log.Info().Str("message_key", key).Bytes("message", data).Send()
+			// the payload is left out, a block message is large and binary
+			log.Info().Str("message_key", key).Uint32("envelope_version", model.EnvelopeVersionOf(data)).Int("message_size", len(data)).Send()
 		})
 	}
 	return
@@ -0,0 +1,17 @@
+package blob
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// Store keeps the payloads too large for the message queue, a key is a slash separated path
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	// Get returns ErrNotFound when the key does not exist
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete does nothing when the key does not exist
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,93 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync-ethereum/pkg/blob"
+)
+
+type FilesystemOption struct {
+	// root directory of the blobs, it is shared by the producers and consumers
+	Dir string
+}
+
+var _ blob.Store = (*FilesystemStore)(nil)
+
+func NewFilesystemStore(option FilesystemOption) (*FilesystemStore, error) {
+	if option.Dir == "" {
+		return nil, errors.New("dir is empty")
+	}
+	dir, err := filepath.Abs(option.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FilesystemStore{dir: dir}, nil
+}
+
+type FilesystemStore struct {
+	dir string
+}
+
+func (s *FilesystemStore) Put(ctx context.Context, key string, data []byte) error {
+	path, err := s._Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// written to a temporary file and renamed, so a reader never sees a partial blob
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (s *FilesystemStore) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := s._Path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", blob.ErrNotFound, key)
+	}
+	return data, err
+}
+
+func (s *FilesystemStore) Delete(ctx context.Context, key string) error {
+	path, err := s._Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// _Path maps a key into the root directory, a key escaping it is rejected
+func (s *FilesystemStore) _Path(key string) (string, error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, s.dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid blob key [%s]", key)
+	}
+	return path, nil
+}
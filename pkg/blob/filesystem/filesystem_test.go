@@ -0,0 +1,83 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync-ethereum/pkg/blob"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewFilesystemStore(FilesystemOption{Dir: filepath.Join(root, "blob")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		key  string
+		want string // empty when the key is rejected
+	}{
+		{"nested key", "blocks/100/0xabc", filepath.Join(root, "blob", "blocks", "100", "0xabc")},
+		{"cleaned inside the root", "blocks/../0xabc", filepath.Join(root, "blob", "0xabc")},
+		{"leading slash", "/blocks/100", filepath.Join(root, "blob", "blocks", "100")},
+		{"parent", "../escaped", ""},
+		{"nested parent", "blocks/../../escaped", ""},
+		{"sibling with the root as prefix", "../blob-evil/escaped", ""},
+		{"root itself", "", ""},
+		{"root by dot", ".", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store._Path(tt.key)
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("got %s, want the key rejected", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilesystemStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFilesystemStore(FilesystemOption{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := "blocks/100/0xabc"
+	if _, err := store.Get(ctx, key); !errors.Is(err, blob.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, blob.ErrNotFound)
+	}
+	for _, data := range []string{"first", "overwritten"} {
+		if err := store.Put(ctx, key, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+		got, err := store.Get(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != data {
+			t.Fatalf("got %s, want %s", got, data)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := store.Delete(ctx, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, blob.ErrNotFound) {
+		t.Fatalf("got error %v after delete, want %v", err, blob.ErrNotFound)
+	}
+	if err := store.Put(ctx, "../escaped", []byte("data")); err == nil {
+		t.Fatal("got no error for a key escaping the root")
+	}
+}
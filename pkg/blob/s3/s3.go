@@ -0,0 +1,234 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync-ethereum/pkg/blob"
+	"time"
+)
+
+const (
+	_Algorithm      = "AWS4-HMAC-SHA256"
+	_Service        = "s3"
+	_TimeFormat     = "20060102T150405Z"
+	_DateFormat     = "20060102"
+	_EmptySha256Hex = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+type S3Option struct {
+	// such as `https://s3.us-east-1.amazonaws.com` or `http://localhost:9000` for MinIO
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// prefix of the object keys
+	Prefix string
+	// address the bucket by the path instead of the host, MinIO needs it
+	PathStyle bool
+	Timeout   time.Duration
+}
+
+var _ blob.Store = (*S3Store)(nil)
+
+func NewS3Store(option S3Option) (*S3Store, error) {
+	if option.Bucket == "" {
+		return nil, errors.New("bucket is empty")
+	}
+	if option.Region == "" {
+		option.Region = "us-east-1"
+	}
+	if option.Timeout <= 0 {
+		option.Timeout = 30 * time.Second
+	}
+	endpoint, err := url.Parse(option.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint [%s]", option.Endpoint)
+	}
+	return &S3Store{
+		option:   option,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: option.Timeout},
+	}, nil
+}
+
+// S3Store stores the blobs as the objects of an S3 compatible bucket, the requests are signed with signature version 4
+type S3Store struct {
+	option   S3Option
+	endpoint *url.URL
+	client   *http.Client
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	resp, err := s._Do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s._Error(resp, key)
+	}
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s._Do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", blob.ErrNotFound, key)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, s._Error(resp, key)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s._Do(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// deleting a missing object succeeds with 204 as well
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s._Error(resp, key)
+	}
+	return nil
+}
+
+func (s *S3Store) _Do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	u := *s.endpoint
+	objectKey := strings.TrimLeft(s.option.Prefix+key, "/")
+	if s.option.PathStyle {
+		u.Path = strings.TrimRight(u.Path, "/") + "/" + s.option.Bucket + "/" + objectKey
+	} else {
+		u.Host = s.option.Bucket + "." + u.Host
+		u.Path = strings.TrimRight(u.Path, "/") + "/" + objectKey
+	}
+	u.RawPath = _EscapePath(u.Path) // sent as it is signed
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	payloadHash := _EmptySha256Hex
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+	_Sign(req, s.option.AccessKeyID, s.option.SecretAccessKey, s.option.Region, payloadHash, time.Now().UTC())
+	return s.client.Do(req)
+}
+
+func (s *S3Store) _Error(resp *http.Response, key string) error {
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 %s %s: %s %s", resp.Request.Method, key, resp.Status, bytes.TrimSpace(message))
+}
+
+// _Sign adds the signature version 4 authorization header, the host, x-amz-* and range headers are signed
+func _Sign(req *http.Request, accessKeyID, secretAccessKey, region, payloadHash string, now time.Time) {
+	amzDate := now.Format(_TimeFormat)
+	date := now.Format(_DateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "range" || name == "content-md5" {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		_EscapePath(req.URL.Path),
+		_CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/" + _Service + "/aws4_request"
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{_Algorithm, amzDate, scope, hex.EncodeToString(canonicalRequestHash[:])}, "\n")
+
+	key := _HmacSha256([]byte("AWS4"+secretAccessKey), date)
+	key = _HmacSha256(key, region)
+	key = _HmacSha256(key, _Service)
+	key = _HmacSha256(key, "aws4_request")
+	signature := hex.EncodeToString(_HmacSha256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", _Algorithm, accessKeyID, scope, signedHeaders, signature))
+}
+
+func _HmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// _EscapePath encodes every byte of the path except the unreserved characters and `/`
+func _EscapePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return _Escape(path, true)
+}
+
+// _Escape encodes every byte except the unreserved characters, and `/` when it is kept
+func _Escape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' || (keepSlash && c == '/') {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func _CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := []string{}
+	for _, k := range keys {
+		values := query[k]
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, _Escape(k, false)+"="+_Escape(v, false))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
@@ -2,12 +2,12 @@ package confluentkafka
 
 import (
 	"context"
-	"encoding/json"
 	"os"
 	"strings"
 	"sync"
 	"sync-ethereum/pkg/mq"
 	"sync-ethereum/pkg/util"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/google/uuid"
@@ -63,13 +63,25 @@ func NewConfluentKafka(option KafkaOption, logger zerolog.Logger) (*ConfluentKaf
 		lock:         sync.RWMutex{},
 		callbackChan: make(map[string]chan MsgData, 0),
 	}
-	c, closeConsumer, err := _NewConsumer(option, logger, callbackChan)
+	offsetTracker := _NewOffsetTracker()
+	c, closeConsumer, err := _NewConsumer(option, logger, callbackChan, offsetTracker)
 	if err != nil {
 		return nil, err
 	}
+	if option.RedeliveryDelayMs <= 0 {
+		option.RedeliveryDelayMs = 1000
+	}
+	if option.MaxRedeliveries <= 0 {
+		option.MaxRedeliveries = 5
+	}
+	if option.ReleaseDelayMs <= 0 {
+		option.ReleaseDelayMs = 30000
+	}
 	return &ConfluentKafka{
 		isRunning:     true,
 		option:        option,
+		logger:        logger,
+		offsetTracker: offsetTracker,
 		producer:      p,
 		consumer:      c,
 		callbackChan:  callbackChan,
@@ -79,7 +91,7 @@ func NewConfluentKafka(option KafkaOption, logger zerolog.Logger) (*ConfluentKaf
 	}, nil
 }
 
-func _NewConsumer(option KafkaOption, logger zerolog.Logger, callbackChan *_CallbackChannelMap) (*kafka.Consumer, func() error, error) {
+func _NewConsumer(option KafkaOption, logger zerolog.Logger, callbackChan *_CallbackChannelMap, offsetTracker *_OffsetTracker) (*kafka.Consumer, func() error, error) {
 	servers := strings.Join(option.Brokers, ",")
 	if option.HeartbeatIntervalMs <= 0 {
 		option.HeartbeatIntervalMs = 3000
@@ -135,6 +147,7 @@ func _NewConsumer(option KafkaOption, logger zerolog.Logger, callbackChan *_Call
 		"heartbeat.interval.ms":         option.HeartbeatIntervalMs,
 		"session.timeout.ms":            option.SessionTimeoutMs,
 		"enable.auto.commit":            option.EnableAutoCommit,
+		"enable.auto.offset.store":      false, // stored by _OffsetTracker after the messages are processed
 		"max.partition.fetch.bytes":     option.MaxPartitionFetchBytes,
 		"fetch.max.bytes":               option.FetchMaxBytes,
 		"group.instance.id":             option.GroupInstanceID,
@@ -166,23 +179,12 @@ func _NewConsumer(option KafkaOption, logger zerolog.Logger, callbackChan *_Call
 					receivedTopic = *e.TopicPartition.Topic
 				}
 
-				var msgData MsgData
-				err := json.Unmarshal(e.Value, &msgData)
+				msgData, err := _UnmarshalMsgData(e)
 				if err != nil {
-					logger.Error().Msgf("fail to unmarshal to internal msgData: %s", msgData)
+					logger.Error().Err(err).Msgf("fail to unmarshal to internal msgData: %s", e.Value)
 					continue
 				}
 				msgData.ConsumeID = uuid.New().String()
-				msgData.Commit = func() error {
-					if option.EnableAutoCommit {
-						return nil
-					}
-					_, err := c.CommitMessage(e)
-					if err != nil {
-						return errors.Wrap(err, "commit error")
-					}
-					return nil
-				}
 				logger.Debug().
 					Str("request_id", msgData.RequestID).
 					Str("consume_id", msgData.ConsumeID).
@@ -199,6 +201,23 @@ func _NewConsumer(option KafkaOption, logger zerolog.Logger, callbackChan *_Call
 				messageChan, ok := callbackChan.Get(receivedTopic)
 
 				if ok && isRunning {
+					tp := e.TopicPartition
+					epoch := offsetTracker.Track(tp)
+					msgData.Commit = func() error {
+						stored, err := offsetTracker.Done(tp, epoch, func(next kafka.TopicPartition) error {
+							if _, err := c.StoreOffsets([]kafka.TopicPartition{next}); err != nil {
+								return errors.Wrap(err, "store offset error")
+							}
+							return nil
+						})
+						if err != nil || !stored || option.EnableAutoCommit {
+							return err
+						}
+						return _CommitStored(c)
+					}
+					msgData.Release = func(resumeAfter time.Duration) error {
+						return _Release(c, offsetTracker, tp, resumeAfter)
+					}
 					messageChan <- msgData
 				}
 
@@ -230,6 +249,11 @@ func _NewConsumer(option KafkaOption, logger zerolog.Logger, callbackChan *_Call
 
 	return c, func() error {
 		isRunning = false
+		if !option.EnableAutoCommit {
+			if err := _CommitStored(c); err != nil {
+				logger.Error().Err(err).Msg("commit stored offsets before close error")
+			}
+		}
 		if err := c.Close(); err != nil {
 			return err
 		}
@@ -237,6 +261,39 @@ func _NewConsumer(option KafkaOption, logger zerolog.Logger, callbackChan *_Call
 	}, nil
 }
 
+// _Release pauses the partition and seeks it back to the message, so the worker is free and the offsets after it are not committed,
+// a partition paused for good is fetched again by the consumer it is assigned to after the next rebalance
+func _Release(c *kafka.Consumer, offsetTracker *_OffsetTracker, tp kafka.TopicPartition, resumeAfter time.Duration) error {
+	partition := []kafka.TopicPartition{{Topic: tp.Topic, Partition: tp.Partition}}
+	if err := c.Pause(partition); err != nil {
+		return errors.Wrap(err, "pause partition error")
+	}
+	offsetTracker.Release(tp)
+	if err := c.Seek(tp, 0); err != nil {
+		return errors.Wrap(err, "seek partition error")
+	}
+	if resumeAfter > 0 {
+		time.AfterFunc(resumeAfter, func() {
+			// the partition may be revoked in the meantime, resuming it is an error then
+			_ = c.Resume(partition)
+		})
+	}
+	return nil
+}
+
+// _CommitStored commits the stored offsets of the assigned partitions
+func _CommitStored(c *kafka.Consumer) error {
+	_, err := c.Commit()
+	var kafkaErr kafka.Error
+	if errors.As(err, &kafkaErr) && kafkaErr.Code() == kafka.ErrNoOffset { // nothing stored since the last commit
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "commit error")
+	}
+	return nil
+}
+
 func _NewProducer(option KafkaOption, logger zerolog.Logger) (*kafka.Producer, func(), error) {
 	servers := strings.Join(option.Brokers, ",")
 	var acks string
@@ -293,6 +350,8 @@ func _NewProducer(option KafkaOption, logger zerolog.Logger) (*kafka.Producer, f
 type ConfluentKafka struct {
 	isRunning     bool
 	option        KafkaOption
+	logger        zerolog.Logger
+	offsetTracker *_OffsetTracker
 	producer      *kafka.Producer
 	consumer      *kafka.Consumer
 	callbackChan  *_CallbackChannelMap
@@ -305,23 +364,18 @@ func (mq *ConfluentKafka) Publish(topic, key string, data []byte) error {
 	if !mq.isRunning {
 		return nil
 	}
-	msgData := MsgData{
-		RequestID: key,
-		Data:      data,
-	}
-
-	b, err := json.Marshal(msgData)
+	value, headers, err := _MarshalMsgData(key, data, mq.option.RawFormat)
 	if err != nil {
-		return errors.New("fail to marshal internal MsgData, err: " + err.Error())
+		return err
 	}
-
 	kafkaMsg := &kafka.Message{
 		TopicPartition: kafka.TopicPartition{
 			Topic:     &topic,
 			Partition: kafka.PartitionAny,
 		},
-		Key:   []byte(key),
-		Value: b,
+		Key:     []byte(key),
+		Value:   value,
+		Headers: headers,
 	}
 
 	mq.producer.ProduceChannel() <- kafkaMsg
@@ -335,13 +389,32 @@ func (mq *ConfluentKafka) Subscribe(ctx context.Context, workerSize int, topic s
 	messageChan := make(chan MsgData, 0)
 	mq.callbackChan.Put(topic, messageChan)
 
-	err := mq.consumer.Subscribe(topic, nil)
+	err := mq.consumer.Subscribe(topic, mq._Rebalance)
 	if err != nil {
 		return err
 	}
 	return mq._StartSubscribeWorker(ctx, workerSize, messageChan, process, errCallBack...)
 }
 
+// _Rebalance commits the progress of the revoked partitions before they are handed to another consumer,
+// the partitions are assigned or unassigned by the client after it returns, incrementally with cooperative-sticky
+func (mq *ConfluentKafka) _Rebalance(c *kafka.Consumer, event kafka.Event) error {
+	switch e := event.(type) {
+	case kafka.AssignedPartitions:
+		mq.logger.Info().Str("kafka.protocol", c.GetRebalanceProtocol()).Interface("kafka.partitions", e.Partitions).Msg("partitions assigned")
+	case kafka.RevokedPartitions:
+		// the offsets of lost partitions may be committed by the new owner already
+		if !c.AssignmentLost() && !mq.option.EnableAutoCommit {
+			if err := _CommitStored(c); err != nil {
+				mq.logger.Error().Err(err).Msg("commit stored offsets before revoke error")
+			}
+		}
+		mq.offsetTracker.Revoke(e.Partitions)
+		mq.logger.Info().Str("kafka.protocol", c.GetRebalanceProtocol()).Bool("kafka.lost", c.AssignmentLost()).Interface("kafka.partitions", e.Partitions).Msg("partitions revoked")
+	}
+	return nil
+}
+
 func (mq *ConfluentKafka) _StartSubscribeWorker(ctx context.Context, workerSize int, messageChan <-chan MsgData, process func(key string, data []byte) (bool, error), errCallBack ...func(string, error)) error {
 	errGroup := errgroup.Group{}
 	for i := 0; i < workerSize; i++ {
@@ -373,19 +446,40 @@ func (mq *ConfluentKafka) _StartSubscribeWorker(ctx context.Context, workerSize
 						return process(key, data)
 					}
 
+					// a nacked message is processed again by the same worker up to MaxRedeliveries times,
+					// then it is released, the offsets after it are not committed until it is acked
 					isAck, err := f(m.RequestID, m.Data)
-					if err != nil {
-						for _, cb := range errCallBack {
-							cb(m.RequestID, err)
-						}
-					}
-					if isAck && mq.isRunning {
-						err := m.Commit()
+					unprocessable := false
+					for redelivery := 0; ; redelivery++ {
 						if err != nil {
 							for _, cb := range errCallBack {
 								cb(m.RequestID, err)
 							}
 						}
+						unprocessable = _IsUnprocessable(err)
+						if isAck || unprocessable || redelivery >= mq.option.MaxRedeliveries ||
+							!mq._Sleep(ctx, time.Duration(mq.option.RedeliveryDelayMs)*time.Millisecond) {
+							break
+						}
+						isAck, err = f(m.RequestID, m.Data)
+					}
+					if !mq.isRunning {
+						continue
+					}
+					if isAck {
+						err = m.Commit()
+					} else {
+						// a message for a newer consumer waits on its paused partition until an upgraded consumer is assigned it
+						resumeAfter := time.Duration(mq.option.ReleaseDelayMs) * time.Millisecond
+						if unprocessable {
+							resumeAfter = 0
+						}
+						err = m.Release(resumeAfter)
+					}
+					if err != nil {
+						for _, cb := range errCallBack {
+							cb(m.RequestID, err)
+						}
 					}
 				case <-ctx.Done():
 					return
@@ -398,6 +492,20 @@ func (mq *ConfluentKafka) _StartSubscribeWorker(ctx context.Context, workerSize
 	return errGroup.Wait()
 }
 
+func _IsUnprocessable(err error) bool {
+	return errors.Is(err, mq.ErrUnprocessable)
+}
+
+// _Sleep returns false when it is stopped by ctx or Close
+func (mq *ConfluentKafka) _Sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return mq.isRunning
+	case <-ctx.Done():
+		return false
+	}
+}
+
 func (mq *ConfluentKafka) SubscriberMiddleware(middleware ...func(key string, data []byte)) {
 	mq.subMiddleware = append(mq.subMiddleware, middleware...)
 }
@@ -1,8 +1,55 @@
 package confluentkafka
 
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// the value of a message with the raw format header is the data itself and the key is the request id,
+// the value of a message without it is a json MsgData, written by the previous versions
+const (
+	_FormatHeader = "sync-ethereum-format"
+	_FormatRaw    = "raw"
+)
+
 type MsgData struct {
 	RequestID string       `json:"request_id"`
 	Data      []byte       `json:"data,omitempty"`
 	ConsumeID string       `json:"consume_id"`
 	Commit    func() error `json:"-"`
+	// Release gives the message back without committing it, it is fetched again after resumeAfter, or after the partition is reassigned when resumeAfter is 0
+	Release func(resumeAfter time.Duration) error `json:"-"`
+}
+
+// _MarshalMsgData writes the raw format only when all the consumers read it, the previous versions drop it as an invalid json
+func _MarshalMsgData(key string, data []byte, raw bool) ([]byte, []kafka.Header, error) {
+	if raw {
+		return data, []kafka.Header{{Key: _FormatHeader, Value: []byte(_FormatRaw)}}, nil
+	}
+	b, err := json.Marshal(MsgData{
+		RequestID: key,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, nil, errors.New("fail to marshal internal MsgData, err: " + err.Error())
+	}
+	return b, nil, nil
+}
+
+func _UnmarshalMsgData(e *kafka.Message) (MsgData, error) {
+	for _, header := range e.Headers {
+		if header.Key == _FormatHeader && string(header.Value) == _FormatRaw {
+			return MsgData{
+				RequestID: string(e.Key),
+				Data:      e.Value,
+			}, nil
+		}
+	}
+
+	var msgData MsgData
+	err := json.Unmarshal(e.Value, &msgData)
+	return msgData, err
 }
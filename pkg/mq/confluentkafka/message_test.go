@@ -0,0 +1,35 @@
+package confluentkafka
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+func TestMsgData(t *testing.T) {
+	data := []byte{0x08, 0x01, 0x7b} // binary, not valid json
+	for _, raw := range []bool{false, true} {
+		value, headers, err := _MarshalMsgData("key", data, raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := _UnmarshalMsgData(&kafka.Message{Key: []byte("key"), Value: value, Headers: headers})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.RequestID != "key" || string(got.Data) != string(data) {
+			t.Fatalf("raw %t got %+v", raw, got)
+		}
+	}
+
+	// a consumer of the previous versions reads the value as a json MsgData and ignores the headers
+	value, _, err := _MarshalMsgData("key", data, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := MsgData{}
+	if err := json.Unmarshal(value, &legacy); err != nil || legacy.RequestID != "key" || string(legacy.Data) != string(data) {
+		t.Fatalf("got %+v %v for the previous consumers", legacy, err)
+	}
+}
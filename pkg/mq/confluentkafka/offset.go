@@ -0,0 +1,132 @@
+package confluentkafka
+
+import (
+	"sync"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+type _Partition struct {
+	topic     string
+	partition int32
+}
+
+// _PartitionOffset keeps the offsets of a partition in the order they are delivered,
+// the offsets of a partition are not always contiguous numbers, such as after compaction
+type _PartitionOffset struct {
+	epoch   uint64
+	pending []kafka.Offset
+	done    map[kafka.Offset]bool
+	// the partition is sought back to the offset, the messages fetched before the seek are not tracked until it is delivered again
+	seeking bool
+	seek    kafka.Offset
+}
+
+// _OffsetTracker stores only the highest offset below which all the delivered messages of a partition are processed,
+// so a message processed by a fast worker never commits a slower message before it
+type _OffsetTracker struct {
+	lock       sync.Mutex
+	epoch      uint64
+	partitions map[_Partition]*_PartitionOffset
+}
+
+func _NewOffsetTracker() *_OffsetTracker {
+	return &_OffsetTracker{
+		partitions: make(map[_Partition]*_PartitionOffset),
+	}
+}
+
+// Track records a delivered message, it returns the epoch of the partition assignment to pass to Done
+func (t *_OffsetTracker) Track(tp kafka.TopicPartition) uint64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	p := _Partition{topic: *tp.Topic, partition: tp.Partition}
+	offset, ok := t.partitions[p]
+	if !ok {
+		t.epoch++
+		offset = &_PartitionOffset{
+			epoch: t.epoch,
+			done:  make(map[kafka.Offset]bool),
+		}
+		t.partitions[p] = offset
+	}
+	if offset.seeking && tp.Offset != offset.seek {
+		return offset.epoch // not pending, so Done ignores it
+	}
+	offset.seeking = false
+	offset.pending = append(offset.pending, tp.Offset)
+	return offset.epoch
+}
+
+// Done marks a message processed and stores the next offset to commit when the processed offsets advance,
+// a message of a revoked partition is ignored, it belongs to another consumer now
+func (t *_OffsetTracker) Done(tp kafka.TopicPartition, epoch uint64, store func(kafka.TopicPartition) error) (stored bool, err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	p := _Partition{topic: *tp.Topic, partition: tp.Partition}
+	offset, ok := t.partitions[p]
+	if !ok || offset.epoch != epoch || !offset._Pending(tp.Offset) {
+		return false, nil
+	}
+
+	offset.done[tp.Offset] = true
+	advanced := false
+	var next kafka.Offset
+	for len(offset.pending) > 0 && offset.done[offset.pending[0]] {
+		next = offset.pending[0] + 1
+		delete(offset.done, offset.pending[0])
+		offset.pending = offset.pending[1:]
+		advanced = true
+	}
+	if !advanced {
+		return false, nil
+	}
+	// stored under the lock, so the stored offset of a partition never goes backwards
+	if err := store(kafka.TopicPartition{Topic: tp.Topic, Partition: tp.Partition, Offset: next}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Revoke drops the partitions, the messages still being processed are delivered again to the new owner
+func (t *_OffsetTracker) Revoke(partitions []kafka.TopicPartition) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for _, tp := range partitions {
+		if tp.Topic == nil {
+			continue
+		}
+		delete(t.partitions, _Partition{topic: *tp.Topic, partition: tp.Partition})
+	}
+}
+
+// Release drops the message and the ones delivered after it, the partition is sought back to the message by the caller,
+// so the messages are tracked again from it when they are delivered again
+func (t *_OffsetTracker) Release(tp kafka.TopicPartition) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	offset, ok := t.partitions[_Partition{topic: *tp.Topic, partition: tp.Partition}]
+	if !ok {
+		return
+	}
+	for i, pending := range offset.pending {
+		if pending == tp.Offset {
+			for _, dropped := range offset.pending[i:] {
+				delete(offset.done, dropped)
+			}
+			offset.pending = offset.pending[:i]
+			break
+		}
+	}
+	offset.seeking = true
+	offset.seek = tp.Offset
+}
+
+func (offset *_PartitionOffset) _Pending(o kafka.Offset) bool {
+	for _, pending := range offset.pending {
+		if pending == o {
+			return true
+		}
+	}
+	return false
+}
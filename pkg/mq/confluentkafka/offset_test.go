@@ -0,0 +1,161 @@
+package confluentkafka
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+func TestOffsetTracker(t *testing.T) {
+	type step struct {
+		track  []kafka.Offset // offsets delivered before the message is done
+		done   kafka.Offset
+		revoke bool // the partition is revoked and assigned again before the message is done
+		want   kafka.Offset
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "in order",
+			steps: []step{
+				{track: []kafka.Offset{0, 1}, done: 0, want: 1},
+				{done: 1, want: 2},
+			},
+		},
+		{
+			name: "out of order",
+			steps: []step{
+				{track: []kafka.Offset{0, 1, 2}, done: 2, want: -1},
+				{done: 1, want: -1},
+				{done: 0, want: 3},
+			},
+		},
+		{
+			name: "gap in offsets",
+			steps: []step{
+				{track: []kafka.Offset{5, 9, 10}, done: 9, want: -1},
+				{done: 5, want: 10},
+				{done: 10, want: 11},
+			},
+		},
+		{
+			name: "revoked partition",
+			steps: []step{
+				{track: []kafka.Offset{0, 1}, revoke: true, done: 0, want: -1},
+				{track: []kafka.Offset{1}, done: 1, want: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topic := "topic"
+			tracker := _NewOffsetTracker()
+			epochs := map[kafka.Offset]uint64{}
+			for i, s := range tt.steps {
+				for _, offset := range s.track {
+					epochs[offset] = tracker.Track(kafka.TopicPartition{Topic: &topic, Partition: 0, Offset: offset})
+				}
+				if s.revoke {
+					tracker.Revoke([]kafka.TopicPartition{{Topic: &topic, Partition: 0}})
+				}
+
+				stored := kafka.Offset(-1)
+				ok, err := tracker.Done(kafka.TopicPartition{Topic: &topic, Partition: 0, Offset: s.done}, epochs[s.done], func(tp kafka.TopicPartition) error {
+					stored = tp.Offset
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if stored != s.want || ok != (s.want >= 0) {
+					t.Fatalf("step %d got stored %d (%t), want %d", i, stored, ok, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestOffsetTrackerPartitions(t *testing.T) {
+	topic, another := "topic", "another"
+	tracker := _NewOffsetTracker()
+	first := tracker.Track(kafka.TopicPartition{Topic: &topic, Partition: 0, Offset: 0})
+	second := tracker.Track(kafka.TopicPartition{Topic: &topic, Partition: 1, Offset: 0})
+	third := tracker.Track(kafka.TopicPartition{Topic: &another, Partition: 0, Offset: 0})
+
+	stored := []kafka.TopicPartition{}
+	store := func(tp kafka.TopicPartition) error {
+		stored = append(stored, tp)
+		return nil
+	}
+	for _, tp := range []struct {
+		topic     *string
+		partition int32
+		epoch     uint64
+	}{{&another, 0, third}, {&topic, 1, second}, {&topic, 0, first}} {
+		if _, err := tracker.Done(kafka.TopicPartition{Topic: tp.topic, Partition: tp.partition, Offset: 0}, tp.epoch, store); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(stored) != 3 {
+		t.Fatalf("got %d stored offsets, want 3", len(stored))
+	}
+	for _, tp := range stored {
+		if tp.Offset != 1 {
+			t.Fatalf("got stored %v, want offset 1", tp)
+		}
+	}
+
+	storeErr := errors.New("store error")
+	epoch := tracker.Track(kafka.TopicPartition{Topic: &topic, Partition: 0, Offset: 1})
+	ok, err := tracker.Done(kafka.TopicPartition{Topic: &topic, Partition: 0, Offset: 1}, epoch, func(kafka.TopicPartition) error {
+		return storeErr
+	})
+	if ok || !errors.Is(err, storeErr) {
+		t.Fatalf("got %t %v, want the store error", ok, err)
+	}
+}
+
+func TestOffsetTrackerRelease(t *testing.T) {
+	topic := "topic"
+	tracker := _NewOffsetTracker()
+	track := func(offset kafka.Offset) uint64 {
+		return tracker.Track(kafka.TopicPartition{Topic: &topic, Partition: 0, Offset: offset})
+	}
+	done := func(offset kafka.Offset, epoch uint64) kafka.Offset {
+		stored := kafka.Offset(-1)
+		if _, err := tracker.Done(kafka.TopicPartition{Topic: &topic, Partition: 0, Offset: offset}, epoch, func(tp kafka.TopicPartition) error {
+			stored = tp.Offset
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return stored
+	}
+
+	epoch := track(0)
+	track(1)
+	track(2)
+	tracker.Release(kafka.TopicPartition{Topic: &topic, Partition: 0, Offset: 1})
+	if got := done(2, epoch); got != -1 {
+		t.Fatalf("got stored %d for a message after the released one, want none", got)
+	}
+	if got := done(0, epoch); got != 1 {
+		t.Fatalf("got stored %d for the message before the released one, want 1", got)
+	}
+
+	// fetched before the seek, it is delivered again after the released message
+	if got := done(3, track(3)); got != -1 {
+		t.Fatalf("got stored %d for a message fetched before the seek, want none", got)
+	}
+	track(1)
+	track(2)
+	if got := done(2, epoch); got != -1 {
+		t.Fatalf("got stored %d before the released message is done, want none", got)
+	}
+	if got := done(1, epoch); got != 3 {
+		t.Fatalf("got stored %d, want 3", got)
+	}
+}
@@ -31,11 +31,20 @@ type KafkaOption struct {
 	Retries         int
 	BatchSize       int
 	FlushWaitMs     int
+	// publish the data as the message value with a format header instead of a json MsgData,
+	// enable it after all the consumers read the raw format
+	RawFormat bool
 
 	// ================ consumer related config ================
 	FetchMaxBytes          int
 	MaxPartitionFetchBytes int
 	PollTimeoutMs          int
+	// delay before a nacked message is processed again
+	RedeliveryDelayMs int
+	// times a nacked message is processed again by the same worker before its partition is paused and sought back to it
+	MaxRedeliveries int
+	// delay before a partition paused for a nacked message is fetched again
+	ReleaseDelayMs int
 
 	// ================ auth ================
 	SASlUserName    string
@@ -19,16 +19,25 @@ type KafkaOption struct {
 	OffsetsInitial int64
 	FetchDefault   int32
 	RequiredAcks   int16
+	// publish the messages of the same key to the same partition, so they are processed in order,
+	// the parallelism of a subscriber is bounded by the partitions of the topic either way
+	PartitionByKey bool
 	LoggerAdapter  watermill.LoggerAdapter
 }
 
 var _ mq.MQ = (*KafkaMQ)(nil)
 
 func NewKafkaMQ(option KafkaOption) (*KafkaMQ, error) {
+	var marshaler kafka.Marshaler = kafka.DefaultMarshaler{}
+	if option.PartitionByKey {
+		marshaler = kafka.NewWithPartitioningMarshaler(func(topic string, msg *message.Message) (string, error) {
+			return msg.UUID, nil
+		})
+	}
 	publisher, err := kafka.NewPublisher(
 		kafka.PublisherConfig{
 			Brokers:   option.Brokers,
-			Marshaler: kafka.DefaultMarshaler{},
+			Marshaler: marshaler,
 		}, option.LoggerAdapter,
 	)
 	if err != nil {
@@ -95,56 +104,64 @@ func (mq *KafkaMQ) Close() error {
 	return mq.subscriber.Close()
 }
 
+// _StartSubscribeWorker processes the messages with workerSize workers,
+// the subscriber holds back the next message of a partition until the current one is acked or nacked,
+// so the messages of a partition are processed in order and the parallelism is bounded by the assigned partitions
 func (mq *KafkaMQ) _StartSubscribeWorker(ctx context.Context, workerSize int, messageChan <-chan *message.Message,
 	process func(key string, data []byte) (bool, error), errCallBack ...func(string, error)) error {
+	if workerSize <= 0 {
+		workerSize = 1
+	}
 
-	// non supporte worker
 	errGroup := errgroup.Group{}
-	errGroup.Go(func() (err error) {
-		defer func() {
-			if recoverErr := util.ConvertRecoverToError(recover()); recoverErr != nil {
-				err = recoverErr
-			}
-		}()
-
-		for {
-			select {
-			case m := <-messageChan:
-				if m == nil {
-					continue
+	for i := 0; i < workerSize; i++ {
+		errGroup.Go(func() (err error) {
+			defer func() {
+				if recoverErr := util.ConvertRecoverToError(recover()); recoverErr != nil {
+					err = recoverErr
 				}
+			}()
 
-				for _, mid := range mq.subMiddleware {
-					mid(m.UUID, m.Payload)
-				}
+			for {
+				select {
+				case m, ok := <-messageChan:
+					if !ok { // the subscriber is closed
+						return
+					}
 
-				// recover panic
-				f := func(key string, data []byte) (ack bool, err error) {
-					defer func() {
-						if recoverErr := util.ConvertRecoverToError(recover()); recoverErr != nil {
-							err = recoverErr
-							ack = true
-						}
-					}()
-					return process(m.UUID, m.Payload)
-				}
+					for _, mid := range mq.subMiddleware {
+						mid(m.UUID, m.Payload)
+					}
 
-				isAck, err := f(m.UUID, m.Payload)
-				if err != nil {
-					for _, cb := range errCallBack {
-						cb(m.UUID, err)
+					// recover panic
+					f := func(key string, data []byte) (ack bool, err error) {
+						defer func() {
+							if recoverErr := util.ConvertRecoverToError(recover()); recoverErr != nil {
+								err = recoverErr
+								ack = true
+							}
+						}()
+						return process(key, data)
 					}
+
+					isAck, err := f(m.UUID, m.Payload)
+					if err != nil {
+						for _, cb := range errCallBack {
+							cb(m.UUID, err)
+						}
+					}
+					// a message is acked or nacked only once by the worker holding it
+					if isAck {
+						m.Ack()
+					} else {
+						m.Nack()
+					}
+				case <-ctx.Done():
+					return
 				}
-				if isAck {
-					m.Ack()
-				} else {
-					m.Nack()
-				}
-			case <-ctx.Done():
-				return
 			}
-		}
-	})
+		})
+	}
 
 	return errGroup.Wait()
 }
@@ -0,0 +1,205 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"sync-ethereum/pkg/mq"
+	"sync-ethereum/pkg/util"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+)
+
+type MemoryOption struct {
+	// delay before a nacked message is delivered again
+	RedeliveryDelay time.Duration
+}
+
+type _Message struct {
+	key  string
+	data []byte
+}
+
+// _Topic is an unbounded queue shared by all the subscribers of the topic,
+// so publishing from a worker never blocks on its own topic
+type _Topic struct {
+	lock     sync.Mutex
+	messages *list.List
+	ready    chan struct{}
+}
+
+func _NewTopic() *_Topic {
+	return &_Topic{
+		messages: list.New(),
+		ready:    make(chan struct{}, 1),
+	}
+}
+
+func (t *_Topic) Push(m _Message) {
+	t.lock.Lock()
+	t.messages.PushBack(m)
+	t.lock.Unlock()
+	t._Notify()
+}
+
+// Pop waits for the next message until ctx is done or the queue is closed
+func (t *_Topic) Pop(ctx context.Context, closed <-chan struct{}) (_Message, bool) {
+	for {
+		t.lock.Lock()
+		if front := t.messages.Front(); front != nil {
+			t.messages.Remove(front)
+			remain := t.messages.Len()
+			t.lock.Unlock()
+			if remain > 0 {
+				t._Notify() // wake up another worker
+			}
+			return front.Value.(_Message), true
+		}
+		t.lock.Unlock()
+
+		select {
+		case <-t.ready:
+		case <-ctx.Done():
+			return _Message{}, false
+		case <-closed:
+			return _Message{}, false
+		}
+	}
+}
+
+func (t *_Topic) _Notify() {
+	select {
+	case t.ready <- struct{}{}:
+	default:
+	}
+}
+
+var _ mq.MQ = (*MemoryMQ)(nil)
+
+// NewMemoryMQ returns the in-process queue, every subscriber of a topic competes for its messages
+// and the messages are lost when the process exits
+func NewMemoryMQ(option MemoryOption) *MemoryMQ {
+	if option.RedeliveryDelay <= 0 {
+		option.RedeliveryDelay = time.Second
+	}
+	return &MemoryMQ{
+		option:        option,
+		topics:        make(map[string]*_Topic),
+		closed:        make(chan struct{}),
+		subMiddleware: make([]func(key string, data []byte), 0),
+	}
+}
+
+type MemoryMQ struct {
+	option        MemoryOption
+	lock          sync.Mutex
+	topics        map[string]*_Topic
+	closed        chan struct{}
+	closeOnce     sync.Once
+	subMiddleware []func(key string, data []byte)
+}
+
+func (mq *MemoryMQ) _Topic(name string) *_Topic {
+	mq.lock.Lock()
+	defer mq.lock.Unlock()
+	topic, ok := mq.topics[name]
+	if !ok {
+		topic = _NewTopic()
+		mq.topics[name] = topic
+	}
+	return topic
+}
+
+func (mq *MemoryMQ) _IsClosed() bool {
+	select {
+	case <-mq.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (mq *MemoryMQ) Publish(topic, key string, data []byte) error {
+	if mq._IsClosed() {
+		return nil
+	}
+	if len(key) == 0 {
+		key = uuid.New().String()
+	}
+	mq._Topic(topic).Push(_Message{key: key, data: data})
+	return nil
+}
+
+func (mq *MemoryMQ) Subscribe(ctx context.Context, workerSize int, topic string, process func(key string, data []byte) (bool, error), errCallBack ...func(string, error)) error {
+	if process == nil {
+		return errors.New("process is nil function")
+	}
+	if workerSize <= 0 {
+		workerSize = 1
+	}
+	return mq._StartSubscribeWorker(ctx, workerSize, mq._Topic(topic), process, errCallBack...)
+}
+
+func (mq *MemoryMQ) _StartSubscribeWorker(ctx context.Context, workerSize int, topic *_Topic, process func(key string, data []byte) (bool, error), errCallBack ...func(string, error)) error {
+	errGroup := errgroup.Group{}
+	for i := 0; i < workerSize; i++ {
+		errGroup.Go(func() (err error) {
+			defer func() {
+				if recoverErr := util.ConvertRecoverToError(recover()); recoverErr != nil {
+					err = recoverErr
+				}
+			}()
+
+			for {
+				m, ok := topic.Pop(ctx, mq.closed)
+				if !ok {
+					return nil
+				}
+				for _, mid := range mq.subMiddleware {
+					mid(m.key, m.data)
+				}
+
+				// recover panic
+				f := func(key string, data []byte) (ack bool, err error) {
+					defer func() {
+						if recoverErr := util.ConvertRecoverToError(recover()); recoverErr != nil {
+							err = recoverErr
+							ack = true
+						}
+					}()
+					return process(key, data)
+				}
+
+				isAck, err := f(m.key, m.data)
+				if err != nil {
+					for _, cb := range errCallBack {
+						cb(m.key, err)
+					}
+				}
+				if !isAck {
+					time.AfterFunc(mq.option.RedeliveryDelay, func() {
+						if !mq._IsClosed() {
+							topic.Push(m)
+						}
+					})
+				}
+			}
+		})
+	}
+
+	return errGroup.Wait()
+}
+
+func (mq *MemoryMQ) SubscriberMiddleware(middleware ...func(key string, data []byte)) {
+	mq.subMiddleware = append(mq.subMiddleware, middleware...)
+}
+
+func (mq *MemoryMQ) Close() error {
+	mq.closeOnce.Do(func() {
+		close(mq.closed)
+	})
+	return nil
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryMQ(t *testing.T) {
+	tests := []struct {
+		name       string
+		workerSize int
+		messages   int
+		nacks      int // times every message is nacked before it is acked
+	}{
+		{"single worker", 1, 10, 0},
+		{"competing workers", 8, 100, 0},
+		{"redelivered after nack", 4, 10, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := NewMemoryMQ(MemoryOption{RedeliveryDelay: time.Millisecond})
+			for i := 0; i < tt.messages; i++ {
+				if err := queue.Publish("topic", fmt.Sprint(i), []byte(fmt.Sprint(i))); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			lock := sync.Mutex{}
+			deliveries := map[string]int{}
+			acked := 0
+			done := make(chan struct{})
+			subscribed := make(chan error, 1)
+			go func() {
+				subscribed <- queue.Subscribe(context.Background(), tt.workerSize, "topic", func(key string, data []byte) (bool, error) {
+					if key != string(data) {
+						t.Errorf("got key %s with data %s", key, data)
+					}
+					lock.Lock()
+					defer lock.Unlock()
+					deliveries[key]++
+					if deliveries[key] <= tt.nacks {
+						return false, nil
+					}
+					if acked++; acked == tt.messages {
+						close(done)
+					}
+					return true, nil
+				})
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout waiting for the messages")
+			}
+			if err := queue.Close(); err != nil {
+				t.Fatal(err)
+			}
+			select {
+			case err := <-subscribed:
+				if err != nil {
+					t.Fatalf("subscribe: %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("subscribe does not return after close")
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			for i := 0; i < tt.messages; i++ {
+				if got := deliveries[fmt.Sprint(i)]; got != tt.nacks+1 {
+					t.Fatalf("message %d delivered %d times, want %d", i, got, tt.nacks+1)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryMQPublishAfterClose(t *testing.T) {
+	queue := NewMemoryMQ(MemoryOption{})
+	if err := queue.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.Publish("topic", "key", []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if n := queue._Topic("topic").messages.Len(); n != 0 {
+		t.Fatalf("got %d messages after close, want 0", n)
+	}
+}
@@ -2,8 +2,13 @@ package mq
 
 import (
 	"context"
+	"errors"
 )
 
+// ErrUnprocessable is wrapped by the error of a message this consumer can never process, e.g. a newer message version,
+// the message is left unacknowledged without retry or dead-letter so an upgraded consumer can process it
+var ErrUnprocessable = errors.New("unprocessable message")
+
 type MQ interface {
 	Publish(topic, key string, data []byte) error
 	Subscribe(ctx context.Context, workerSize int, topic string, process func(key string, data []byte) (bool, error), errCallBack ...func(string, error)) error
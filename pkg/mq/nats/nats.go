@@ -0,0 +1,373 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync-ethereum/pkg/mq"
+	"sync-ethereum/pkg/util"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/errgroup"
+)
+
+const _KeyHeader = "Sync-Ethereum-Key"
+
+type NatsOption struct {
+	URL      string
+	Username string
+	Password string
+	Token    string
+	// the stream of a topic is named by the prefix and the topic
+	StreamPrefix string
+	// `file` or `memory`
+	Storage  string
+	Replicas int
+	// max age of the messages in a stream, 0 is unlimited
+	MaxAge time.Duration
+	// all the subscribers sharing the same durable consumer compete for the messages of a stream
+	Durable string
+	// max deliveries of a message, the message is dropped by the server after it, -1 is unlimited
+	MaxDeliver int
+	// an unacknowledged message is delivered again after AckWait
+	AckWait time.Duration
+	// max unacknowledged messages of the consumer
+	MaxAckPending int
+	// max messages of each fetch
+	FetchBatch int
+	// max duration to wait on an empty stream
+	FetchTimeout time.Duration
+}
+
+var _ mq.MQ = (*NatsMQ)(nil)
+
+func NewNatsMQ(option NatsOption, logger zerolog.Logger) (*NatsMQ, error) {
+	if option.Durable == "" {
+		return nil, errors.New("durable is empty")
+	}
+	if strings.ContainsAny(option.Durable, ".*> ") {
+		return nil, errors.New("invalid durable [" + option.Durable + "]")
+	}
+	storage := nats.FileStorage
+	switch strings.ToLower(option.Storage) {
+	case "", "file":
+	case "memory":
+		storage = nats.MemoryStorage
+	default:
+		return nil, errors.New("no supported storage [" + option.Storage + "]")
+	}
+	if option.Replicas <= 0 {
+		option.Replicas = 1
+	}
+	if option.MaxDeliver == 0 {
+		option.MaxDeliver = -1
+	}
+	if option.AckWait <= 0 {
+		option.AckWait = 30 * time.Second
+	}
+	if option.FetchBatch <= 0 {
+		option.FetchBatch = 10
+	}
+	if option.FetchTimeout <= 0 {
+		option.FetchTimeout = time.Second
+	}
+
+	opts := []nats.Option{nats.MaxReconnects(-1)}
+	if option.Username != "" {
+		opts = append(opts, nats.UserInfo(option.Username, option.Password))
+	}
+	if option.Token != "" {
+		opts = append(opts, nats.Token(option.Token))
+	}
+	conn, err := nats.Connect(option.URL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &NatsMQ{
+		option:        option,
+		storage:       storage,
+		logger:        logger,
+		conn:          conn,
+		js:            js,
+		ctx:           ctx,
+		cancel:        cancel,
+		subMiddleware: make([]func(key string, data []byte), 0),
+	}, nil
+}
+
+type NatsMQ struct {
+	option        NatsOption
+	storage       nats.StorageType
+	logger        zerolog.Logger
+	conn          *nats.Conn
+	js            nats.JetStreamContext
+	ctx           context.Context
+	cancel        context.CancelFunc
+	subMiddleware []func(key string, data []byte)
+
+	// topic -> stream name, the streams already ensured
+	streams sync.Map
+}
+
+func (mq *NatsMQ) Publish(topic, key string, data []byte) error {
+	if mq.ctx.Err() != nil {
+		return nil
+	}
+	if len(key) == 0 {
+		key = uuid.New().String()
+	}
+	if _, err := mq._EnsureStream(topic); err != nil {
+		return err
+	}
+	msg := nats.NewMsg(topic)
+	msg.Header.Set(_KeyHeader, key)
+	msg.Data = data
+	_, err := mq.js.PublishMsg(msg)
+	return err
+}
+
+func (mq *NatsMQ) Subscribe(ctx context.Context, workerSize int, topic string, process func(key string, data []byte) (bool, error), errCallBack ...func(string, error)) error {
+	if process == nil {
+		return errors.New("process is nil function")
+	}
+	if workerSize <= 0 {
+		workerSize = 1
+	}
+	stream, err := mq._EnsureStream(topic)
+	if err != nil {
+		return err
+	}
+	if err := mq._EnsureConsumer(stream, topic); err != nil {
+		return err
+	}
+	sub, err := mq.js.PullSubscribe(topic, mq.option.Durable, nats.BindStream(stream))
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-mq.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	messageChan := make(chan *nats.Msg)
+	errGroup := errgroup.Group{}
+	errGroup.Go(func() error {
+		mq._Fetch(ctx, topic, sub, messageChan)
+		return nil
+	})
+	mq._StartSubscribeWorker(ctx, &errGroup, workerSize, messageChan, process, errCallBack...)
+	return errGroup.Wait()
+}
+
+// _StreamName returns the stream of a topic, a stream name can not contain `.`, `*`, `>` or space
+func (mq *NatsMQ) _StreamName(topic string) string {
+	return mq.option.StreamPrefix + strings.NewReplacer(".", "_", "*", "_", ">", "_", " ", "_").Replace(topic)
+}
+
+// _EnsureStream creates the stream of a topic once, the config of an existing stream is kept
+// as long as it captures the topic
+func (mq *NatsMQ) _EnsureStream(topic string) (string, error) {
+	if stream, ok := mq.streams.Load(topic); ok {
+		return stream.(string), nil
+	}
+	stream := mq._StreamName(topic)
+	info, err := mq.js.AddStream(&nats.StreamConfig{
+		Name:      stream,
+		Subjects:  []string{topic},
+		Retention: nats.LimitsPolicy,
+		MaxAge:    mq.option.MaxAge,
+		Storage:   mq.storage,
+		Replicas:  mq.option.Replicas,
+	})
+	if err != nil {
+		// the client of this version reports api errors by description only, so the existing stream is looked up instead
+		existing, infoErr := mq.js.StreamInfo(stream)
+		if infoErr != nil {
+			return "", err
+		}
+		info = existing
+	}
+	if !_CapturesSubject(info.Config.Subjects, topic) {
+		return "", fmt.Errorf("stream %s with subjects %v does not capture topic %s", stream, info.Config.Subjects, topic)
+	}
+	mq.streams.Store(topic, stream)
+	return stream, nil
+}
+
+// _CapturesSubject reports whether any of the stream subjects matches the subject, `*` matches a token and `>` the rest
+func _CapturesSubject(subjects []string, subject string) bool {
+	tokens := strings.Split(subject, ".")
+	for _, pattern := range subjects {
+		patternTokens := strings.Split(pattern, ".")
+		for i, patternToken := range patternTokens {
+			if patternToken == ">" && i < len(tokens) {
+				return true
+			}
+			if i >= len(tokens) || (patternToken != "*" && patternToken != tokens[i]) {
+				break
+			}
+			if i == len(patternTokens)-1 && i == len(tokens)-1 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// _EnsureConsumer creates the durable consumer of a stream, the config of an existing consumer is kept
+func (mq *NatsMQ) _EnsureConsumer(stream, topic string) error {
+	if _, err := mq.js.ConsumerInfo(stream, mq.option.Durable); err == nil {
+		return nil
+	}
+	_, err := mq.js.AddConsumer(stream, &nats.ConsumerConfig{
+		Durable:       mq.option.Durable,
+		DeliverPolicy: nats.DeliverAllPolicy,
+		AckPolicy:     nats.AckExplicitPolicy,
+		AckWait:       mq.option.AckWait,
+		MaxDeliver:    mq.option.MaxDeliver,
+		MaxAckPending: mq.option.MaxAckPending,
+		FilterSubject: topic,
+	})
+	return err
+}
+
+func (mq *NatsMQ) _Fetch(ctx context.Context, topic string, sub *nats.Subscription, messageChan chan<- *nats.Msg) {
+	for ctx.Err() == nil {
+		messages, err := sub.Fetch(mq.option.FetchBatch, nats.MaxWait(mq.option.FetchTimeout))
+		if errors.Is(err, nats.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, nats.ErrConnectionClosed) {
+				return
+			}
+			mq.logger.Error().Err(err).Str("nats.subject", topic).Msg("fetch message error")
+			mq._Sleep(ctx, mq.option.FetchTimeout)
+			continue
+		}
+		for _, message := range messages {
+			select {
+			case messageChan <- message:
+			case <-ctx.Done(): // the fetched messages are delivered again after AckWait
+				return
+			}
+		}
+	}
+}
+
+func (mq *NatsMQ) _Sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-ctx.Done():
+	}
+}
+
+func (mq *NatsMQ) _StartSubscribeWorker(ctx context.Context, errGroup *errgroup.Group, workerSize int, messageChan <-chan *nats.Msg,
+	process func(key string, data []byte) (bool, error), errCallBack ...func(string, error)) {
+	for i := 0; i < workerSize; i++ {
+		errGroup.Go(func() (err error) {
+			defer func() {
+				if recoverErr := util.ConvertRecoverToError(recover()); recoverErr != nil {
+					err = recoverErr
+				}
+			}()
+
+			for {
+				select {
+				case m := <-messageChan:
+					key := m.Header.Get(_KeyHeader)
+					for _, mid := range mq.subMiddleware {
+						mid(key, m.Data)
+					}
+
+					// recover panic
+					f := func(key string, data []byte) (ack bool, err error) {
+						defer func() {
+							if recoverErr := util.ConvertRecoverToError(recover()); recoverErr != nil {
+								err = recoverErr
+								ack = true
+							}
+						}()
+						return process(key, data)
+					}
+
+					stop := mq._KeepInProgress(m)
+					isAck, err := f(key, m.Data)
+					stop()
+					if err != nil {
+						for _, cb := range errCallBack {
+							cb(key, err)
+						}
+					}
+					mq._Ack(m, key, isAck, errCallBack...)
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+}
+
+// _KeepInProgress resets the ack timer of a message being processed, so a slow process is not delivered to another subscriber
+func (mq *NatsMQ) _KeepInProgress(m *nats.Msg) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		tick := time.NewTicker(mq.option.AckWait / 2)
+		defer tick.Stop()
+		for {
+			select {
+			case <-tick.C:
+				m.InProgress()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+func (mq *NatsMQ) _Ack(m *nats.Msg, key string, isAck bool, errCallBack ...func(string, error)) {
+	if mq.ctx.Err() != nil {
+		return
+	}
+	var err error
+	if isAck {
+		err = m.Ack()
+	} else { // delivered again until MaxDeliver
+		err = m.Nak()
+	}
+	if err != nil {
+		for _, cb := range errCallBack {
+			cb(key, err)
+		}
+	}
+}
+
+func (mq *NatsMQ) SubscriberMiddleware(middleware ...func(key string, data []byte)) {
+	mq.subMiddleware = append(mq.subMiddleware, middleware...)
+}
+
+func (mq *NatsMQ) Close() error {
+	mq.cancel()
+	mq.conn.Close()
+	return nil
+}
@@ -0,0 +1,30 @@
+package nats
+
+import "testing"
+
+func TestCapturesSubject(t *testing.T) {
+	tests := []struct {
+		name     string
+		subjects []string
+		subject  string
+		want     bool
+	}{
+		{"exact", []string{"eth.crawler"}, "eth.crawler", true},
+		{"another subject", []string{"eth.writer", "eth.crawler"}, "eth.crawler", true},
+		{"different", []string{"eth.writer"}, "eth.crawler", false},
+		{"prefix only", []string{"eth"}, "eth.crawler", false},
+		{"longer subject", []string{"eth.crawler.v2"}, "eth.crawler", false},
+		{"token wildcard", []string{"eth.*"}, "eth.crawler", true},
+		{"token wildcard of one token", []string{"eth.*"}, "eth.crawler.v2", false},
+		{"full wildcard", []string{"eth.>"}, "eth.crawler.v2", true},
+		{"full wildcard needs a token", []string{"eth.>"}, "eth", false},
+		{"no subjects", nil, "eth.crawler", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := _CapturesSubject(tt.subjects, tt.subject); got != tt.want {
+				t.Fatalf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
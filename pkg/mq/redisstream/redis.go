@@ -0,0 +1,338 @@
+package redisstream
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"sync-ethereum/pkg/mq"
+	"sync-ethereum/pkg/util"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	_KeyField  = "key"
+	_DataField = "data"
+)
+
+type RedisStreamOption struct {
+	Addr     string
+	Username string
+	Password string
+	DB       int
+	// all the consumers sharing the same group compete for the messages of a stream
+	ConsumerGroup string
+	// consumer name in the group, default hostname
+	Consumer string
+	// approximate max length of each stream, 0 is unlimited
+	MaxLen int64
+	// max messages of each read
+	BatchSize int64
+	// max duration to block on an empty stream
+	BlockTimeout time.Duration
+	// pending messages idle longer are claimed by this consumer, they are left by a dead consumer,
+	// it is longer than processing a message with all its retries
+	ClaimMinIdle  time.Duration
+	ClaimInterval time.Duration
+	// delay before a nacked message is delivered to this consumer again
+	RedeliveryDelay time.Duration
+}
+
+var _ mq.MQ = (*RedisStreamMQ)(nil)
+
+func NewRedisStreamMQ(option RedisStreamOption, logger zerolog.Logger) (*RedisStreamMQ, error) {
+	if option.ConsumerGroup == "" {
+		return nil, errors.New("consumer group is empty")
+	}
+	if option.Consumer == "" {
+		consumer := os.Getenv("HOSTNAME")
+		if consumer == "" {
+			consumer = uuid.New().String()
+		}
+		option.Consumer = consumer
+	}
+	if option.BatchSize <= 0 {
+		option.BatchSize = 10
+	}
+	if option.BlockTimeout <= 0 {
+		option.BlockTimeout = time.Second
+	}
+	if option.ClaimMinIdle <= 0 {
+		option.ClaimMinIdle = 5 * time.Minute
+	}
+	if option.ClaimInterval <= 0 {
+		option.ClaimInterval = 30 * time.Second
+	}
+	if option.RedeliveryDelay <= 0 {
+		option.RedeliveryDelay = time.Second
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     option.Addr,
+		Username: option.Username,
+		Password: option.Password,
+		DB:       option.DB,
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &RedisStreamMQ{
+		option:        option,
+		logger:        logger,
+		client:        client,
+		ctx:           ctx,
+		cancel:        cancel,
+		subMiddleware: make([]func(key string, data []byte), 0),
+	}, nil
+}
+
+type RedisStreamMQ struct {
+	option        RedisStreamOption
+	logger        zerolog.Logger
+	client        *redis.Client
+	ctx           context.Context
+	cancel        context.CancelFunc
+	subMiddleware []func(key string, data []byte)
+}
+
+func (mq *RedisStreamMQ) Publish(topic, key string, data []byte) error {
+	if mq.ctx.Err() != nil {
+		return nil
+	}
+	if len(key) == 0 {
+		key = uuid.New().String()
+	}
+	return mq.client.XAdd(mq.ctx, &redis.XAddArgs{
+		Stream: topic,
+		MaxLen: mq.option.MaxLen,
+		Approx: true,
+		Values: []interface{}{_KeyField, key, _DataField, data},
+	}).Err()
+}
+
+func (mq *RedisStreamMQ) Subscribe(ctx context.Context, workerSize int, topic string, process func(key string, data []byte) (bool, error), errCallBack ...func(string, error)) error {
+	if process == nil {
+		return errors.New("process is nil function")
+	}
+	if workerSize <= 0 {
+		workerSize = 1
+	}
+	err := mq.client.XGroupCreateMkStream(ctx, topic, mq.option.ConsumerGroup, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") { // the group already exists
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-mq.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	messageChan := make(chan redis.XMessage)
+	inFlight := &sync.Map{} // ids delivered to the workers and not acknowledged yet
+	errGroup := errgroup.Group{}
+	errGroup.Go(func() error {
+		mq._Read(ctx, topic, inFlight, messageChan)
+		return nil
+	})
+	errGroup.Go(func() error {
+		mq._Claim(ctx, topic, inFlight, messageChan)
+		return nil
+	})
+	mq._StartSubscribeWorker(ctx, &errGroup, workerSize, topic, inFlight, messageChan, process, errCallBack...)
+	return errGroup.Wait()
+}
+
+// _Read delivers the pending messages of this consumer first, which are left by the previous run, then the new messages
+func (mq *RedisStreamMQ) _Read(ctx context.Context, topic string, inFlight *sync.Map, messageChan chan<- redis.XMessage) {
+	id := "0"
+	for ctx.Err() == nil {
+		streams, err := mq.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    mq.option.ConsumerGroup,
+			Consumer: mq.option.Consumer,
+			Streams:  []string{topic, id},
+			Count:    mq.option.BatchSize,
+			Block:    mq.option.BlockTimeout,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, redis.ErrClosed) {
+				return
+			}
+			mq.logger.Error().Err(err).Str("redis.stream", topic).Msg("read stream error")
+			mq._Sleep(ctx, mq.option.BlockTimeout)
+			continue
+		}
+
+		received := 0
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				received++
+				if id != ">" {
+					id = message.ID
+				}
+				inFlight.Store(message.ID, struct{}{})
+				if !mq._Deliver(ctx, messageChan, message) {
+					return
+				}
+			}
+		}
+		if id != ">" && received == 0 {
+			id = ">" // no more pending message
+		}
+	}
+}
+
+// _Claim takes over the messages which stay pending longer than ClaimMinIdle,
+// they are left by a dead consumer, the messages still in flight of this consumer are skipped
+func (mq *RedisStreamMQ) _Claim(ctx context.Context, topic string, inFlight *sync.Map, messageChan chan<- redis.XMessage) {
+	tick := time.NewTicker(mq.option.ClaimInterval)
+	defer tick.Stop()
+	for {
+		start := "0-0"
+		for {
+			messages, next, err := mq.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+				Stream:   topic,
+				Group:    mq.option.ConsumerGroup,
+				Consumer: mq.option.Consumer,
+				MinIdle:  mq.option.ClaimMinIdle,
+				Start:    start,
+				Count:    mq.option.BatchSize,
+			}).Result()
+			if err != nil {
+				if ctx.Err() != nil || errors.Is(err, redis.ErrClosed) {
+					return
+				}
+				mq.logger.Error().Err(err).Str("redis.stream", topic).Msg("claim pending message error")
+				break
+			}
+			for _, message := range messages {
+				if _, ok := inFlight.LoadOrStore(message.ID, struct{}{}); ok {
+					continue
+				}
+				mq.logger.Warn().Str("redis.stream", topic).Str("redis.id", message.ID).Msg("claim pending message")
+				if !mq._Deliver(ctx, messageChan, message) {
+					return
+				}
+			}
+			if next == "0-0" {
+				break
+			}
+			start = next
+		}
+
+		select {
+		case <-tick.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (mq *RedisStreamMQ) _Deliver(ctx context.Context, messageChan chan<- redis.XMessage, message redis.XMessage) bool {
+	select {
+	case messageChan <- message:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (mq *RedisStreamMQ) _Sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-ctx.Done():
+	}
+}
+
+func (mq *RedisStreamMQ) _StartSubscribeWorker(ctx context.Context, errGroup *errgroup.Group, workerSize int, topic string, inFlight *sync.Map, messageChan chan redis.XMessage,
+	process func(key string, data []byte) (bool, error), errCallBack ...func(string, error)) {
+	for i := 0; i < workerSize; i++ {
+		errGroup.Go(func() (err error) {
+			defer func() {
+				if recoverErr := util.ConvertRecoverToError(recover()); recoverErr != nil {
+					err = recoverErr
+				}
+			}()
+
+			for {
+				select {
+				case m := <-messageChan:
+					key, _ := m.Values[_KeyField].(string)
+					data, ok := m.Values[_DataField].(string)
+					if !ok { // deleted by trimming or not written by this driver
+						mq._Ack(topic, m.ID, key, errCallBack...)
+						inFlight.Delete(m.ID)
+						continue
+					}
+					for _, mid := range mq.subMiddleware {
+						mid(key, []byte(data))
+					}
+
+					// recover panic
+					f := func(key string, data []byte) (ack bool, err error) {
+						defer func() {
+							if recoverErr := util.ConvertRecoverToError(recover()); recoverErr != nil {
+								err = recoverErr
+								ack = true
+							}
+						}()
+						return process(key, data)
+					}
+
+					isAck, err := f(key, []byte(data))
+					if err != nil {
+						for _, cb := range errCallBack {
+							cb(key, err)
+						}
+					}
+					if !isAck { // the unacknowledged message stays in flight until it is delivered again
+						time.AfterFunc(mq.option.RedeliveryDelay, func() {
+							mq._Deliver(ctx, messageChan, m)
+						})
+						continue
+					}
+					mq._Ack(topic, m.ID, key, errCallBack...)
+					inFlight.Delete(m.ID)
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+}
+
+func (mq *RedisStreamMQ) _Ack(topic, id, key string, errCallBack ...func(string, error)) {
+	if mq.ctx.Err() != nil {
+		return
+	}
+	if err := mq.client.XAck(mq.ctx, topic, mq.option.ConsumerGroup, id).Err(); err != nil {
+		for _, cb := range errCallBack {
+			cb(key, err)
+		}
+	}
+}
+
+func (mq *RedisStreamMQ) SubscriberMiddleware(middleware ...func(key string, data []byte)) {
+	mq.subMiddleware = append(mq.subMiddleware, middleware...)
+}
+
+func (mq *RedisStreamMQ) Close() error {
+	mq.cancel()
+	return mq.client.Close()
+}
@@ -0,0 +1,204 @@
+package redisstream
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+const (
+	_TestTopic = "topic"
+	_TestGroup = "group"
+)
+
+type _Delivery struct {
+	key  string
+	data string
+}
+
+func _NewTestMQ(t *testing.T, server *miniredis.Miniredis, consumer string) *RedisStreamMQ {
+	t.Helper()
+	queue, err := NewRedisStreamMQ(RedisStreamOption{
+		Addr:            server.Addr(),
+		ConsumerGroup:   _TestGroup,
+		Consumer:        consumer,
+		BlockTimeout:    10 * time.Millisecond,
+		ClaimMinIdle:    50 * time.Millisecond,
+		ClaimInterval:   10 * time.Millisecond,
+		RedeliveryDelay: 10 * time.Millisecond,
+	}, zerolog.Nop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return queue
+}
+
+// _Subscribe processes the messages until want of them are acknowledged, and returns all the deliveries
+func _Subscribe(t *testing.T, queue *RedisStreamMQ, want int, process func(key string, data []byte) bool) []_Delivery {
+	t.Helper()
+	lock := sync.Mutex{}
+	deliveries := []_Delivery{}
+	acked := 0
+	done := make(chan struct{})
+	subscribed := make(chan error, 1)
+	go func() {
+		subscribed <- queue.Subscribe(context.Background(), 1, _TestTopic, func(key string, data []byte) (bool, error) {
+			ack := process(key, data)
+			lock.Lock()
+			defer lock.Unlock()
+			deliveries = append(deliveries, _Delivery{key: key, data: string(data)})
+			if ack {
+				if acked++; acked == want {
+					close(done)
+				}
+			}
+			return ack, nil
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the messages")
+	}
+	time.Sleep(100 * time.Millisecond) // longer than ClaimMinIdle, for the duplicated deliveries
+	queue.Close()
+	select {
+	case err := <-subscribed:
+		if err != nil {
+			t.Fatalf("subscribe: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscribe does not return after close")
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	return deliveries
+}
+
+func _Pending(t *testing.T, server *miniredis.Miniredis) int64 {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	pending, err := client.XPending(context.Background(), _TestTopic, _TestGroup).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pending.Count
+}
+
+// _LeavePending reads the message as the consumer without acknowledging it
+func _LeavePending(t *testing.T, server *miniredis.Miniredis, consumer string) {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	ctx := context.Background()
+	if err := client.XGroupCreateMkStream(ctx, _TestTopic, _TestGroup, "0").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    _TestGroup,
+		Consumer: consumer,
+		Streams:  []string{_TestTopic, ">"},
+	}).Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedisStreamMQ(t *testing.T) {
+	tests := []struct {
+		name string
+		// consumer which reads the pending message before the subscriber starts, empty for none
+		pendingConsumer string
+		process         func(deliveries map[string]int, key string) bool
+		want            []_Delivery
+	}{
+		{
+			name:    "ack",
+			process: func(map[string]int, string) bool { return true },
+			want:    []_Delivery{{"pending", "0"}, {"new", "1"}},
+		},
+		{
+			name:            "pending of this consumer then new",
+			pendingConsumer: "consumer",
+			process:         func(map[string]int, string) bool { return true },
+			want:            []_Delivery{{"pending", "0"}, {"new", "1"}},
+		},
+		{
+			name:            "claim pending of a dead consumer",
+			pendingConsumer: "dead",
+			process:         func(map[string]int, string) bool { return true },
+			want:            []_Delivery{{"pending", "0"}, {"new", "1"}},
+		},
+		{
+			name: "redelivered after nack",
+			process: func(deliveries map[string]int, key string) bool {
+				return deliveries[key] > 2
+			},
+			want: []_Delivery{{"pending", "0"}, {"pending", "0"}, {"pending", "0"}, {"new", "1"}, {"new", "1"}, {"new", "1"}},
+		},
+		{
+			name: "in flight message is not claimed",
+			process: func(deliveries map[string]int, key string) bool {
+				time.Sleep(200 * time.Millisecond) // longer than ClaimMinIdle
+				return true
+			},
+			want: []_Delivery{{"pending", "0"}, {"new", "1"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := miniredis.RunT(t)
+			queue := _NewTestMQ(t, server, "consumer")
+			if err := queue.Publish(_TestTopic, "pending", []byte("0")); err != nil {
+				t.Fatal(err)
+			}
+			if len(tt.pendingConsumer) > 0 {
+				_LeavePending(t, server, tt.pendingConsumer)
+				time.Sleep(100 * time.Millisecond) // longer than ClaimMinIdle
+			}
+			if err := queue.Publish(_TestTopic, "new", []byte("1")); err != nil {
+				t.Fatal(err)
+			}
+
+			lock := sync.Mutex{}
+			counts := map[string]int{}
+			deliveries := _Subscribe(t, queue, 2, func(key string, data []byte) bool {
+				lock.Lock()
+				counts[key]++
+				count := map[string]int{key: counts[key]}
+				lock.Unlock()
+				return tt.process(count, key)
+			})
+
+			got := map[_Delivery]int{}
+			for _, delivery := range deliveries {
+				got[delivery]++
+			}
+			want := map[_Delivery]int{}
+			for _, delivery := range tt.want {
+				want[delivery]++
+			}
+			if len(got) != len(want) {
+				t.Fatalf("got deliveries %v, want %v", deliveries, tt.want)
+			}
+			for delivery, n := range want {
+				if got[delivery] != n {
+					t.Fatalf("got deliveries %v, want %v", deliveries, tt.want)
+				}
+			}
+			if tt.pendingConsumer == "consumer" && deliveries[0].key != "pending" {
+				t.Fatalf("got first delivery %v, want the pending message", deliveries[0])
+			}
+			if pending := _Pending(t, server); pending != 0 {
+				t.Fatalf("got %d pending messages, want 0", pending)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package mq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+type RetryOption struct {
+	// times to process a failed message, including the first one, they are counted in the memory of the consumer,
+	// so a message redelivered after a restart or to another consumer starts over
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	// the message is left unacknowledged after the last attempt when it is empty
+	DeadLetterTopic string
+}
+
+// DeadLetter is the message which still fails after all attempts, it is published to the dead-letter topic
+type DeadLetter struct {
+	Topic    string    `json:"topic"`
+	Key      string    `json:"key"`
+	Data     []byte    `json:"data"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+var _ MQ = (*RetryMQ)(nil)
+
+// WithRetry retries the unacknowledged messages of the queue with backoff,
+// the worker is held during the backoff so the pressure goes to the consumer
+func WithRetry(queue MQ, option RetryOption, logger zerolog.Logger) *RetryMQ {
+	if option.MaxAttempts <= 0 {
+		option.MaxAttempts = 1
+	}
+	return &RetryMQ{
+		MQ:     queue,
+		option: option,
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+}
+
+type RetryMQ struct {
+	MQ
+	option    RetryOption
+	logger    zerolog.Logger
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func (mq *RetryMQ) Subscribe(ctx context.Context, workerSize int, topic string, process func(key string, data []byte) (bool, error), errCallBack ...func(string, error)) error {
+	if process == nil {
+		return errors.New("process is nil function")
+	}
+	return mq.MQ.Subscribe(ctx, workerSize, topic, mq._Retry(topic, process), errCallBack...)
+}
+
+func (mq *RetryMQ) _Retry(topic string, process func(key string, data []byte) (bool, error)) func(key string, data []byte) (bool, error) {
+	return func(key string, data []byte) (bool, error) {
+		backoff := mq.option.Backoff
+		attempts := 0
+		for {
+			attempts++
+			ack, err := process(key, data)
+			if ack {
+				return ack, err
+			}
+			if errors.Is(err, ErrUnprocessable) {
+				return false, err
+			}
+			if attempts >= mq.option.MaxAttempts {
+				return mq._DeadLetter(topic, key, data, attempts, err)
+			}
+
+			mq.logger.Warn().Err(err).Str("topic", topic).Str("message_key", key).Int("attempts", attempts).Dur("backoff", backoff).Msg("process message error, retry")
+			select {
+			case <-time.After(backoff):
+			case <-mq.done:
+				return false, err
+			}
+			if backoff *= 2; mq.option.MaxBackoff > 0 && backoff > mq.option.MaxBackoff {
+				backoff = mq.option.MaxBackoff
+			}
+		}
+	}
+}
+
+// _DeadLetter publishes the message to the dead-letter topic and acknowledges it,
+// messages of the dead-letter topic itself are never dead-lettered again
+func (mq *RetryMQ) _DeadLetter(topic, key string, data []byte, attempts int, err error) (bool, error) {
+	if len(mq.option.DeadLetterTopic) == 0 || topic == mq.option.DeadLetterTopic {
+		return false, err
+	}
+	if err == nil {
+		err = errors.New("message is not acknowledged")
+	}
+
+	letter, marshalErr := json.Marshal(DeadLetter{
+		Topic:    topic,
+		Key:      key,
+		Data:     data,
+		Error:    err.Error(),
+		Attempts: attempts,
+		FailedAt: time.Now().UTC(),
+	})
+	if marshalErr != nil {
+		return false, errors.Wrap(marshalErr, "marshal dead letter error")
+	}
+	if publishErr := mq.MQ.Publish(mq.option.DeadLetterTopic, key, letter); publishErr != nil {
+		return false, errors.Wrap(publishErr, "publish dead letter error")
+	}
+	return true, fmt.Errorf("dead-lettered to %s after %d attempts: %w", mq.option.DeadLetterTopic, attempts, err)
+}
+
+// Close closes the queue once, it is safe to be called by every delivery sharing the queue
+func (mq *RetryMQ) Close() error {
+	mq.closeOnce.Do(func() {
+		close(mq.done)
+		mq.closeErr = mq.MQ.Close()
+	})
+	return mq.closeErr
+}
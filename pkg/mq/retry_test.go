@@ -0,0 +1,89 @@
+package mq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+type _PublishedMessage struct {
+	topic string
+	key   string
+	data  []byte
+}
+
+type _StubMQ struct {
+	published []_PublishedMessage
+}
+
+func (s *_StubMQ) Publish(topic, key string, data []byte) error {
+	s.published = append(s.published, _PublishedMessage{topic: topic, key: key, data: data})
+	return nil
+}
+
+func (s *_StubMQ) Subscribe(ctx context.Context, workerSize int, topic string, process func(key string, data []byte) (bool, error), errCallBack ...func(string, error)) error {
+	return nil
+}
+
+func (s *_StubMQ) SubscriberMiddleware(middleware ...func(key string, data []byte)) {}
+
+func (s *_StubMQ) Close() error {
+	return nil
+}
+
+func TestRetry(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name            string
+		deadLetterTopic string
+		results         []error // nil acknowledges the attempt
+		wantAck         bool
+		wantAttempts    int
+		wantDeadLetter  bool
+	}{
+		{"ack", "dlq", []error{nil}, true, 1, false},
+		{"ack after retry", "dlq", []error{errFailed, errFailed, nil}, true, 3, false},
+		{"dead-lettered", "dlq", []error{errFailed, errFailed, errFailed}, true, 3, true},
+		{"unacknowledged without dead-letter topic", "", []error{errFailed, errFailed, errFailed}, false, 3, false},
+		{"unprocessable", "dlq", []error{fmt.Errorf("%w: newer version", ErrUnprocessable)}, false, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &_StubMQ{}
+			queue := WithRetry(stub, RetryOption{MaxAttempts: 3, DeadLetterTopic: tt.deadLetterTopic}, zerolog.Nop())
+			attempts := 0
+			ack, _ := queue._Retry("topic", func(key string, data []byte) (bool, error) {
+				err := tt.results[attempts]
+				attempts++
+				return err == nil, err
+			})("key", []byte("data"))
+
+			if ack != tt.wantAck {
+				t.Fatalf("got ack %t, want %t", ack, tt.wantAck)
+			}
+			if attempts != tt.wantAttempts {
+				t.Fatalf("got %d attempts, want %d", attempts, tt.wantAttempts)
+			}
+			if !tt.wantDeadLetter {
+				if len(stub.published) > 0 {
+					t.Fatalf("got %d dead letters, want none", len(stub.published))
+				}
+				return
+			}
+			if len(stub.published) != 1 || stub.published[0].topic != tt.deadLetterTopic {
+				t.Fatalf("got published %+v, want one dead letter to %s", stub.published, tt.deadLetterTopic)
+			}
+			letter := DeadLetter{}
+			if err := json.Unmarshal(stub.published[0].data, &letter); err != nil {
+				t.Fatal(err)
+			}
+			if letter.Topic != "topic" || letter.Key != "key" || string(letter.Data) != "data" || letter.Attempts != tt.wantAttempts {
+				t.Fatalf("got dead letter %+v", letter)
+			}
+		})
+	}
+}